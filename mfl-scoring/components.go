@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ComponentMetric is a standings figure beyond fantasy points and record that a league can choose to
+// award championship points for.
+type ComponentMetric string
+
+const (
+	ComponentPointsAgainst    ComponentMetric = "points_against"
+	ComponentPotentialPoints  ComponentMetric = "potential_points"
+	ComponentLineupEfficiency ComponentMetric = "lineup_efficiency"
+)
+
+// ComponentConfig adds a championship point component. Like fantasy points, the best value earns
+// one point per team in the league, the next one fewer, and ties split their places. LowestWins
+// ranks the smallest value best, e.g. to reward the fewest points against.
+type ComponentConfig struct {
+	Metric     ComponentMetric `json:"metric"`
+	LowestWins bool            `json:"lowest_wins"`
+}
+
+func validateComponents(components []ComponentConfig) error {
+	seen := map[ComponentMetric]bool{}
+	for i, component := range components {
+		switch component.Metric {
+		case ComponentPointsAgainst, ComponentPotentialPoints, ComponentLineupEfficiency:
+		default:
+			return fmt.Errorf("component %d: unknown metric %q", i+1, component.Metric)
+		}
+		if seen[component.Metric] {
+			return fmt.Errorf("component %d: %s is scored more than once", i+1, component.Metric)
+		}
+		seen[component.Metric] = true
+	}
+
+	return nil
+}
+
+// componentValue is the standings figure the component ranks.
+func componentValue(franchise Franchise, metric ComponentMetric) float64 {
+	switch metric {
+	case ComponentPointsAgainst:
+		return franchise.PointsAgainst
+	case ComponentPotentialPoints:
+		return franchise.PotentialPoints
+	default:
+		return franchise.LineupEfficiency
+	}
+}
+
+// componentScore is the championship points the franchise was awarded for the component.
+func componentScore(franchise Franchise, metric ComponentMetric) float64 {
+	switch metric {
+	case ComponentPointsAgainst:
+		return franchise.PointsAgainstScore
+	case ComponentPotentialPoints:
+		return franchise.PotentialPointsScore
+	default:
+		return franchise.EfficiencyScore
+	}
+}
+
+func setComponentScore(franchise *Franchise, metric ComponentMetric, score float64) {
+	switch metric {
+	case ComponentPointsAgainst:
+		franchise.PointsAgainstScore = score
+	case ComponentPotentialPoints:
+		franchise.PotentialPointsScore = score
+	default:
+		franchise.EfficiencyScore = score
+	}
+}
+
+// componentLabel names the component in tables and breakdowns.
+func componentLabel(metric ComponentMetric) string {
+	switch metric {
+	case ComponentPointsAgainst:
+		return "points against"
+	case ComponentPotentialPoints:
+		return "potential points"
+	default:
+		return "lineup efficiency"
+	}
+}
+
+// calculateComponentScores awards the configured components. Components that aren't configured are
+// cleared so a rescore never keeps a stale award.
+func calculateComponentScores(franchises Franchises, components []ComponentConfig) Franchises {
+	for i := range franchises.Franchise {
+		f := &franchises.Franchise[i]
+		f.PointsAgainstScore, f.PotentialPointsScore, f.EfficiencyScore = 0, 0, 0
+	}
+
+	for _, component := range components {
+		values := make([]float64, len(franchises.Franchise))
+		for i, franchise := range franchises.Franchise {
+			values[i] = componentValue(franchise, component.Metric)
+		}
+		for i, score := range placeScores(values, component.LowestWins) {
+			setComponentScore(&franchises.Franchise[i], component.Metric, score)
+		}
+	}
+
+	return franchises
+}
+
+// placeScores awards n points for the best of n values down to one for the worst, with equal values
+// sharing the points for the places they cover, the same split calculatePointsScore makes.
+func placeScores(values []float64, lowestWins bool) []float64 {
+	scores := make([]float64, len(values))
+	for i, mine := range values {
+		var count placeCount
+		for j, theirs := range values {
+			if j == i {
+				continue
+			}
+			if lowestWins {
+				count.add(-theirs, -mine)
+			} else {
+				count.add(theirs, mine)
+			}
+		}
+		scores[i] = count.score(len(values))
+	}
+
+	return scores
+}
+
+// lineupEfficiency is the share of its potential points a franchise actually scored.
+func lineupEfficiency(pointsFor, potentialPoints float64) float64 {
+	if potentialPoints <= 0 {
+		return 0
+	}
+
+	return roundFloat(pointsFor/potentialPoints, 4)
+}
+
+func formatEfficiency(efficiency float64) string {
+	return strconv.FormatFloat(efficiency*100, 'f', 1, 64) + "%"
+}
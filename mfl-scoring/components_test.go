@@ -0,0 +1,166 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// componentStandings is two franchises with points against and potential points reported. Team 1
+// scored 1500.5 of a possible 1800, Team 2 1200 of 1300.
+func componentStandings(t *testing.T, components ...ComponentConfig) Standings {
+	t.Helper()
+	standings := testStandings()
+	for i, reported := range []struct{ pa, pp string }{{"1400.25", "1800"}, {"1250", "1300"}} {
+		f := &standings.Franchises.Franchise[i]
+		standing := Franchise{RecordWinsString: strconv.Itoa(f.RecordWins),
+			RecordLossesString: strconv.Itoa(f.RecordLosses), RecordTiesString: strconv.Itoa(f.RecordTies),
+			PointsForString: strconv.FormatFloat(f.PointsFor, 'f', -1, 64), PointsAgainstString: reported.pa,
+			PotentialPointsString: reported.pp}
+		parsed, err := copyStandingsDetails(*f, standing)
+		require.NoError(t, err)
+		*f = parsed
+	}
+
+	standings.Scoring = ScoringConfig{Components: components}
+	standings.Franchises = rankFranchises(scoreChampionship(populateHeadToHeadRecords(standings.Franchises),
+		standings.Scoring), standings.Scoring)
+
+	return standings
+}
+
+func TestValidateComponents(t *testing.T) {
+	testCases := []struct {
+		name       string
+		components []ComponentConfig
+		errMsg     string
+	}{
+		{name: "none"},
+		{name: "all", components: []ComponentConfig{{Metric: ComponentPointsAgainst, LowestWins: true},
+			{Metric: ComponentPotentialPoints}, {Metric: ComponentLineupEfficiency}}},
+		{name: "unknown", components: []ComponentConfig{{Metric: "yards"}},
+			errMsg: `component 1: unknown metric "yards"`},
+		{name: "twice", components: []ComponentConfig{{Metric: ComponentPointsAgainst},
+			{Metric: ComponentPointsAgainst, LowestWins: true}},
+			errMsg: "component 2: points_against is scored more than once"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateComponents(tc.components)
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestPlaceScores(t *testing.T) {
+	values := []float64{10, 30, 20, 20}
+
+	assert.Equal(t, []float64{1, 4, 2.5, 2.5}, placeScores(values, false))
+	assert.Equal(t, []float64{4, 1, 2.5, 2.5}, placeScores(values, true))
+	assert.Empty(t, placeScores(nil, false))
+}
+
+func TestCopyStandingsDetailsPointsAgainstAndPotential(t *testing.T) {
+	standings := componentStandings(t)
+	byID := map[string]Franchise{}
+	for _, franchise := range standings.Franchises.Franchise {
+		byID[franchise.TeamID] = franchise
+	}
+
+	assert.Equal(t, 1400.25, byID["0001"].PointsAgainst)
+	assert.Equal(t, 1800.0, byID["0001"].PotentialPoints)
+	assert.Equal(t, 0.8336, byID["0001"].LineupEfficiency)
+	assert.Equal(t, 0.9231, byID["0002"].LineupEfficiency)
+
+	_, err := copyStandingsDetails(Franchise{}, Franchise{PointsAgainstString: "lots"})
+	assert.Error(t, err)
+	missing, err := copyStandingsDetails(Franchise{}, Franchise{PointsForString: "100"})
+	require.NoError(t, err)
+	assert.Zero(t, missing.LineupEfficiency, "no potential points, no efficiency")
+}
+
+func TestScoreChampionshipComponents(t *testing.T) {
+	// Team 1 leads on fantasy points and record (4.0) but Team 2 is the more efficient (2.0 to 1.0)
+	// and has the fewest points against (2.0 to 1.0).
+	standings := componentStandings(t, ComponentConfig{Metric: ComponentLineupEfficiency},
+		ComponentConfig{Metric: ComponentPointsAgainst, LowestWins: true})
+
+	first, second := standings.Franchises.Franchise[0], standings.Franchises.Franchise[1]
+	assert.Equal(t, "0001", first.TeamID)
+	assert.Equal(t, 1.0, first.EfficiencyScore)
+	assert.Equal(t, 1.0, first.PointsAgainstScore)
+	assert.Equal(t, 6.0, first.TotalScore)
+	assert.Equal(t, 2.0, second.EfficiencyScore)
+	assert.Equal(t, 2.0, second.PointsAgainstScore)
+	assert.Equal(t, 6.0, second.TotalScore)
+	assert.Zero(t, first.PotentialPointsScore, "unconfigured components aren't scored")
+}
+
+func TestComponentColumns(t *testing.T) {
+	plain := printScoringTableUncouthly(testStandings().Franchises)
+	assert.NotContains(t, plain, "PTS AGAINST")
+	assert.NotContains(t, plain, "EFFICIENCY")
+
+	standings := componentStandings(t, ComponentConfig{Metric: ComponentPotentialPoints})
+	table := printScoringTableUncouthly(standings.Franchises)
+	assert.Contains(t, table, "PTS AGAINST")
+	assert.Contains(t, table, "POTENTIAL PTS")
+	assert.Contains(t, table, "83.4%")
+	assert.Contains(t, table, "PP SCORE")
+	assert.NotContains(t, table, "PA SCORE")
+
+	response := newStandingsResponseV1(standings, DisplayPolicy{Mode: DisplayFull}, standings.FetchedAt)
+	require.NotNil(t, response.Franchises[0].PointsAgainst)
+	assert.Equal(t, 1400.25, *response.Franchises[0].PointsAgainst)
+	require.NotNil(t, response.Franchises[0].LineupEfficiency)
+	assert.Equal(t, 0.8336, *response.Franchises[0].LineupEfficiency)
+	assert.Equal(t, 2.0, response.Franchises[0].PotentialPointsScore)
+
+	page := newStandingsPageData(response, DisplayPolicy{Mode: DisplayFull})
+	var labels []string
+	for _, column := range page.Columns {
+		labels = append(labels, column.Label)
+	}
+	assert.Equal(t, []string{"Team Name", "Owner", Record, FantasyPts, PtsAgainst, PotentialPts, Efficiency, PtsScore,
+		RecScore, PPScore, TotalPts, AllPlayRecord, AllPlayPct, Luck}, labels)
+	assert.Equal(t, "83.4%", page.Rows[0][6].Text)
+}
+
+func TestExplainComponents(t *testing.T) {
+	standings := componentStandings(t, ComponentConfig{Metric: ComponentPointsAgainst, LowestWins: true})
+
+	explanations := explainStandings(standings, DisplayPolicy{Mode: DisplayFull})
+	require.Len(t, explanations[0].Components, 1)
+	component := explanations[0].Components[0]
+	assert.Equal(t, "points_against", component.Metric)
+	assert.Equal(t, 1400.25, component.Value)
+	assert.Equal(t, 2, component.Place)
+	assert.Contains(t, explanations[0].Narrative, Team1Name+" had 1400.25 points against, 2nd in the league. "+
+		"That place is worth 1.0 points.")
+	assert.Contains(t, explanations[0].Narrative, "2.0 + 2.0 + 1.0 = 5.0 championship points, 1st of 2.")
+}
+
+func TestProjectScenariosComponents(t *testing.T) {
+	standings := componentStandings(t, ComponentConfig{Metric: ComponentPointsAgainst})
+	franchises := applyDisplayPolicy(standings.Franchises, DisplayPolicy{Mode: DisplayFull}).Franchise
+
+	over := projectScenarios(franchises, nil, standings.Scoring)
+	for _, scenario := range over {
+		assert.Equal(t, scenario.TotalScore, scenario.BestCase, scenario.DisplayName)
+		assert.Equal(t, scenario.TotalScore, scenario.WorstCase, scenario.DisplayName)
+	}
+
+	withGames := projectScenarios(franchises, []Game{{Week: 14, Home: "0001", Away: "0002"}}, standings.Scoring)
+	withoutComponent := projectScenarios(franchises, []Game{{Week: 14, Home: "0001", Away: "0002"}}, ScoringConfig{})
+	for i := range withGames {
+		assert.Equal(t, withoutComponent[i].BestCase+2, withGames[i].BestCase, withGames[i].DisplayName)
+		assert.Equal(t, withoutComponent[i].WorstCase+1, withGames[i].WorstCase, withGames[i].DisplayName)
+	}
+}
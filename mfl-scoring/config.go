@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+const (
+	configEnv     string = "SCORING_CONFIG"
+	configFileEnv string = "SCORING_CONFIG_FILE"
+)
+
+// Config holds the league-specific settings that don't belong in code. It is read from the
+// SCORING_CONFIG environment variable (inline JSON) or the file named by SCORING_CONFIG_FILE.
+type Config struct {
+	Display       DisplayConfig       `json:"display"`
+	Scoring       ScoringConfig       `json:"scoring"`
+	Notifications NotificationsConfig `json:"notifications"`
+	PowerRankings PowerRankingsConfig `json:"power_rankings"`
+	Playoffs      PlayoffsConfig      `json:"playoffs"`
+	Owners        []OwnerConfig       `json:"owners"`
+}
+
+type LeagueFormat string
+
+const (
+	LeagueHeadToHead LeagueFormat = "head_to_head"
+	LeaguePointsOnly LeagueFormat = "points_only"
+)
+
+// MedianMode says how a franchise's record against each week's median score is scored.
+type MedianMode string
+
+const (
+	MedianOff     MedianMode = "off"
+	MedianReplace MedianMode = "replace"
+	MedianAugment MedianMode = "augment"
+)
+
+// RecordMetric is how a record is turned into the value records are ranked by.
+type RecordMetric string
+
+const (
+	// RecordHalfWin counts wins plus half a win per tie.
+	RecordHalfWin RecordMetric = "half_win"
+	// RecordWinPercentage is wins plus half a win per tie over games played.
+	RecordWinPercentage RecordMetric = "win_percentage"
+	// RecordTiesAsLoss is wins over games played, so a tie is as good as a loss.
+	RecordTiesAsLoss RecordMetric = "ties_as_loss"
+	// RecordGamesBack is games behind the best record, the average of the win and loss differences.
+	RecordGamesBack RecordMetric = "games_back"
+)
+
+// ScoringConfig holds the league's championship scoring rules beyond points and record.
+type ScoringConfig struct {
+	// Format is head_to_head or points_only. Left empty it is detected from the league.
+	Format LeagueFormat `json:"format"`
+	// DivisionWinnerBonus is added to the total of the franchise at the top of each division's own
+	// championship table.
+	DivisionWinnerBonus float64 `json:"division_winner_bonus"`
+	// Median scores each week's score against the league median as an extra game: replace scores
+	// that record instead of head to head, augment adds the two together. Empty or off ignores it.
+	Median MedianMode `json:"median"`
+	// RecordMetric ranks records by half_win (the default), win_percentage, ties_as_loss or
+	// games_back. The percentages suit leagues where franchises have played different numbers of games.
+	RecordMetric RecordMetric `json:"record_metric"`
+	// Components award championship points for points against, potential points or lineup
+	// efficiency on top of fantasy points and record.
+	Components []ComponentConfig `json:"components"`
+}
+
+func (c ScoringConfig) validate() error {
+	switch c.Format {
+	case "", LeagueHeadToHead, LeaguePointsOnly:
+	default:
+		return fmt.Errorf("unknown league format %q", c.Format)
+	}
+
+	switch c.Median {
+	case "", MedianOff, MedianReplace, MedianAugment:
+	default:
+		return fmt.Errorf("unknown median mode %q", c.Median)
+	}
+
+	switch c.RecordMetric {
+	case "", RecordHalfWin, RecordWinPercentage, RecordTiesAsLoss, RecordGamesBack:
+	default:
+		return fmt.Errorf("unknown record metric %q", c.RecordMetric)
+	}
+
+	if c.DivisionWinnerBonus < 0 {
+		return errors.New("division_winner_bonus can't be negative")
+	}
+
+	return validateComponents(c.Components)
+}
+
+// resolveFormat prefers the configured league format over the detected one.
+func (c ScoringConfig) resolveFormat(detected LeagueFormat) LeagueFormat {
+	if c.Format != "" {
+		return c.Format
+	}
+
+	return detected
+}
+
+// scoresMedian reports whether the league counts records against the weekly median.
+func (c ScoringConfig) scoresMedian() bool {
+	return c.Median == MedianReplace || c.Median == MedianAugment
+}
+
+var (
+	loadedConfig    Config
+	loadedConfigErr error
+	configOnce      sync.Once
+)
+
+// currentConfig loads the configuration once per container.
+func currentConfig() (Config, error) {
+	configOnce.Do(func() {
+		loadedConfig, loadedConfigErr = loadConfig(os.Getenv(configEnv), os.Getenv(configFileEnv))
+	})
+
+	return loadedConfig, loadedConfigErr
+}
+
+func loadConfig(inline, path string) (Config, error) {
+	config := defaultConfig()
+
+	raw := []byte(inline)
+	if inline == "" && path != "" {
+		var err error
+		raw, err = os.ReadFile(path) //nolint:gosec // The path comes from the deployment, not a request.
+		if err != nil {
+			return Config{}, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+
+	if len(raw) == 0 {
+		return config, nil
+	}
+
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return Config{}, fmt.Errorf("parsing scoring config: %w", err)
+	}
+
+	if err := config.validate(); err != nil {
+		return Config{}, err
+	}
+
+	return config, nil
+}
+
+func defaultConfig() Config {
+	return Config{Display: defaultDisplayConfig(), PowerRankings: defaultPowerRankingsConfig(),
+		Playoffs: defaultPlayoffsConfig()}
+}
+
+func (c Config) validate() error {
+	if err := c.Display.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Scoring.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Notifications.validate(); err != nil {
+		return err
+	}
+
+	if err := c.PowerRankings.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Playoffs.validate(); err != nil {
+		return err
+	}
+
+	return validateOwners(c.Owners)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"display": {"default": "alias", "aliases": {"0001": "Commish"}}}`), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	testCases := []struct {
+		name        string
+		inline      string
+		path        string
+		expected    DisplayMode
+		expectError bool
+	}{
+		{name: "defaults", expected: DisplayFull},
+		{name: "inline", inline: `{"display": {"default": "team_id"}}`, expected: DisplayTeamID},
+		{name: "file", path: path, expected: DisplayAlias},
+		{name: "inline wins over file", inline: `{"display": {"default": "owner_first_name"}}`, path: path,
+			expected: DisplayOwnerFirstName},
+		{name: "missing file", path: filepath.Join(dir, "missing.json"), expectError: true},
+		{name: "bad JSON", inline: `{`, expectError: true},
+		{name: "unknown mode", inline: `{"display": {"stages": {"prod": "everything"}}}`, expectError: true},
+		{name: "unknown league format", inline: `{"scoring": {"format": "roto"}}`, expectError: true},
+		{name: "median", inline: `{"scoring": {"median": "augment"}}`, expected: DisplayFull},
+		{name: "unknown median mode", inline: `{"scoring": {"median": "always"}}`, expectError: true},
+		{name: "record metric", inline: `{"scoring": {"record_metric": "win_percentage"}}`, expected: DisplayFull},
+		{name: "unknown record metric", inline: `{"scoring": {"record_metric": "elo"}}`, expectError: true},
+		{name: "power rankings", inline: `{"power_rankings": {"weights": {"record": 1}}}`, expected: DisplayFull},
+		{name: "bad power rankings", inline: `{"power_rankings": {"recent_weeks": 0}}`, expectError: true},
+		{name: "owners", inline: `{"owners": [{"id": "kim", "franchises": [{"franchise_id": "0001"}]}]}`,
+			expected: DisplayFull},
+		{name: "bad owners", inline: `{"owners": [{"id": "kim"}]}`, expectError: true},
+		{name: "playoffs", inline: `{"playoffs": {"teams": 6, "division_winners_first": true}}`, expected: DisplayFull},
+		{name: "bad playoffs", inline: `{"playoffs": {"teams": 1}}`, expectError: true},
+		{name: "bad playoff byes", inline: `{"playoffs": {"teams": 6, "byes": 1}}`, expectError: true},
+		{name: "negative bonus", inline: `{"scoring": {"division_winner_bonus": -1}}`, expectError: true},
+		{name: "bad webhook", inline: `{"notifications": {"snapshot": "/tmp/s.json", "webhooks": [{"kind": "irc"}]}}`,
+			expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := loadConfig(tc.inline, tc.path)
+			if (err != nil) != tc.expectError {
+				t.Fatalf("loadConfig() error = %v, expectError %v", err, tc.expectError)
+			}
+			if tc.expectError {
+				return
+			}
+			assert.Equal(t, tc.expected, config.Display.Default)
+			assert.Equal(t, DisplayTeamID, config.Display.Domains["execute-api"])
+		})
+	}
+}
+
+func TestLoadConfigPowerRankings(t *testing.T) {
+	config, err := loadConfig(`{"power_rankings": {"weights": {"record": 1}}}`, "")
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	assert.Equal(t, 3, config.PowerRankings.RecentWeeks)
+	assert.Equal(t, PowerRankingWeights{RecentForm: 0.35, PointsFor: 0.25, AllPlay: 0.25, Record: 1},
+		config.PowerRankings.Weights, "unset weights keep their defaults")
+}
+
+func TestLoadConfigDomainsReplaceDefault(t *testing.T) {
+	config, err := loadConfig(`{"display": {"domains": {"preview.example.com": "alias"}}}`, "")
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	assert.Equal(t, map[string]DisplayMode{"preview.example.com": DisplayAlias}, config.Display.Domains)
+	assert.Equal(t, DisplayFull, config.Display.Default, "other display defaults are kept")
+}
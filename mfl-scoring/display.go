@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type DisplayMode string
+
+const (
+	DisplayFull           DisplayMode = "full"
+	DisplayOwnerFirstName DisplayMode = "owner_first_name"
+	DisplayTeamID         DisplayMode = "team_id"
+	DisplayAlias          DisplayMode = "alias"
+)
+
+// DisplayConfig picks a display mode for each request. The most specific match wins: route, then
+// domain substring, then stage, then the default. Aliases are used by the alias mode.
+type DisplayConfig struct {
+	Default DisplayMode            `json:"default"`
+	Routes  map[string]DisplayMode `json:"routes"`
+	Domains map[string]DisplayMode `json:"domains"`
+	Stages  map[string]DisplayMode `json:"stages"`
+	Aliases map[string]string      `json:"aliases"`
+}
+
+// DisplayPolicy is the resolved choice for a single request.
+type DisplayPolicy struct {
+	Mode    DisplayMode
+	Aliases map[string]string
+}
+
+// defaultDisplayConfig keeps the original behavior: full names on the custom domains and team IDs
+// on the raw execute-api URL.
+func defaultDisplayConfig() DisplayConfig {
+	return DisplayConfig{
+		Default: DisplayFull,
+		Domains: map[string]DisplayMode{"execute-api": DisplayTeamID},
+	}
+}
+
+// UnmarshalJSON replaces the default domains when the config lists its own, rather than adding to
+// them, so the execute-api entry can be dropped.
+func (c *DisplayConfig) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if _, ok := fields["domains"]; ok {
+		c.Domains = nil
+	}
+
+	type plain DisplayConfig
+	return json.Unmarshal(data, (*plain)(c))
+}
+
+func (c DisplayConfig) validate() error {
+	modes := []DisplayMode{c.Default}
+	for _, group := range []map[string]DisplayMode{c.Routes, c.Domains, c.Stages} {
+		for _, mode := range group {
+			modes = append(modes, mode)
+		}
+	}
+
+	for _, mode := range modes {
+		switch mode {
+		case DisplayFull, DisplayOwnerFirstName, DisplayTeamID, DisplayAlias:
+		default:
+			return fmt.Errorf("unknown display mode %q", mode)
+		}
+	}
+
+	return nil
+}
+
+// policyFor resolves the display policy for a request served by the route with the given pattern
+// ("/" for the standings table).
+func (c DisplayConfig) policyFor(routeName string, request events.APIGatewayProxyRequest) DisplayPolicy {
+	policy := DisplayPolicy{Mode: c.Default, Aliases: c.Aliases}
+
+	if mode, ok := c.Stages[request.RequestContext.Stage]; ok {
+		policy.Mode = mode
+	}
+
+	domains := make([]string, 0, len(c.Domains))
+	for domain := range c.Domains {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	for _, domain := range domains {
+		if strings.Contains(request.RequestContext.DomainName, domain) {
+			policy.Mode = c.Domains[domain]
+			break
+		}
+	}
+
+	if mode, ok := c.Routes[routeName]; ok {
+		policy.Mode = mode
+	}
+
+	return policy
+}
+
+// HidesTeamNames reports whether the table should show a single label column instead of team and
+// owner names.
+func (p DisplayPolicy) HidesTeamNames() bool {
+	return p.Mode != DisplayFull
+}
+
+// applyDisplayPolicy returns a copy of the franchises with every name the policy hides removed and
+// DisplayName set to the label renderers should show. Renderers only ever see this copy, so a
+// hidden name cannot reach any output format.
+func applyDisplayPolicy(franchises Franchises, policy DisplayPolicy) Franchises {
+	redacted := Franchises{Franchise: make([]Franchise, len(franchises.Franchise))}
+
+	for i, franchise := range franchises.Franchise {
+		switch policy.Mode {
+		case DisplayFull:
+			franchise.DisplayName = franchise.TeamName
+		case DisplayOwnerFirstName:
+			franchise.OwnerName = ownerLabel(franchise)
+			franchise.TeamName = ""
+			franchise.DisplayName = franchise.OwnerName
+		case DisplayAlias:
+			franchise.DisplayName = franchise.TeamID
+			if alias, ok := policy.Aliases[franchise.TeamID]; ok && alias != "" {
+				franchise.DisplayName = alias
+			}
+			if franchise.OwnerAlias != "" {
+				franchise.DisplayName = franchise.OwnerAlias
+			}
+			franchise.TeamName = ""
+			franchise.OwnerName = ""
+			franchise.OwnerID = ""
+			franchise.OwnerAlias = ""
+		default:
+			franchise.DisplayName = franchise.TeamID
+			franchise.TeamName = ""
+			franchise.OwnerName = ""
+			franchise.OwnerID = ""
+			franchise.OwnerAlias = ""
+		}
+
+		if franchise.DisplayName == "" {
+			franchise.DisplayName = franchise.TeamID
+		}
+
+		redacted.Franchise[i] = franchise
+	}
+
+	return redacted
+}
+
+// ownerLabel is the owner's registry alias, or the first name of the owner when there isn't one.
+func ownerLabel(franchise Franchise) string {
+	if franchise.OwnerAlias != "" {
+		return franchise.OwnerAlias
+	}
+
+	return firstName(franchise.OwnerName)
+}
+
+func firstName(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return fields[0]
+}
+
+// displayLabelHeader is the column title for DisplayName under each mode.
+func displayLabelHeader(mode DisplayMode) string {
+	switch mode {
+	case DisplayOwnerFirstName:
+		return "Owner"
+	case DisplayAlias:
+		return "Team"
+	default:
+		return "Team ID"
+	}
+}
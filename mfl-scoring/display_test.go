@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	privateTeamName  = "Uncouth Team Name"
+	privateOwnerName = "Firstname Secretsurname"
+)
+
+func privateFranchises() Franchises {
+	return Franchises{
+		Franchise: []Franchise{
+			{TeamID: "0001", TeamName: privateTeamName, OwnerName: privateOwnerName, Record: "1-0-0",
+				PointsForString: "100.0", TotalScoreString: "2.0"},
+			{TeamID: "0002", TeamName: Team2Name, OwnerName: Team2Owner, Record: "0-1-0",
+				PointsForString: "90.0", TotalScoreString: "1.0"},
+		},
+	}
+}
+
+// outputRenderers lists every output format so the privacy guarantees below cover all of them.
+func outputRenderers() map[string]func(Franchises, DisplayPolicy) string {
+	rendered := map[string]func(Franchises, DisplayPolicy) string{}
+	for format, r := range renderers() {
+		render := r.render
+		rendered[string(format)] = func(franchises Franchises, policy DisplayPolicy) string {
+			body, err := render(Standings{Franchises: franchises}, policy, time.Now())
+			if err != nil {
+				panic(err)
+			}
+			return body
+		}
+	}
+
+	return rendered
+}
+
+func TestHiddenNamesNeverRendered(t *testing.T) {
+	testCases := []struct {
+		policy    DisplayPolicy
+		forbidden []string
+		required  []string
+	}{
+		{
+			policy:    DisplayPolicy{Mode: DisplayTeamID},
+			forbidden: []string{privateTeamName, "Firstname", "Secretsurname", Team2Name, Team2Owner},
+			required:  []string{"0001", "0002"},
+		},
+		{
+			policy:    DisplayPolicy{Mode: DisplayOwnerFirstName},
+			forbidden: []string{privateTeamName, "Secretsurname", Team2Name},
+			required:  []string{"Firstname"},
+		},
+		{
+			policy:    DisplayPolicy{Mode: DisplayAlias, Aliases: map[string]string{"0001": "The Commish"}},
+			forbidden: []string{privateTeamName, "Firstname", "Secretsurname", Team2Name, Team2Owner},
+			required:  []string{"The Commish", "0002"},
+		},
+		{
+			policy:   DisplayPolicy{Mode: DisplayFull},
+			required: []string{privateTeamName, privateOwnerName},
+		},
+	}
+
+	for _, tc := range testCases {
+		for format, render := range outputRenderers() {
+			t.Run(string(tc.policy.Mode)+"/"+format, func(t *testing.T) {
+				output := render(privateFranchises(), tc.policy)
+				for _, forbidden := range tc.forbidden {
+					assert.NotContains(t, output, forbidden)
+				}
+				for _, required := range tc.required {
+					assert.Contains(t, output, required)
+				}
+			})
+		}
+	}
+}
+
+func TestApplyDisplayPolicyDoesNotModifyInput(t *testing.T) {
+	franchises := privateFranchises()
+	applyDisplayPolicy(franchises, DisplayPolicy{Mode: DisplayTeamID})
+
+	assert.Equal(t, privateTeamName, franchises.Franchise[0].TeamName)
+}
+
+func TestPolicyFor(t *testing.T) {
+	config := DisplayConfig{
+		Default: DisplayFull,
+		Stages:  map[string]DisplayMode{"stage": DisplayOwnerFirstName},
+		Domains: map[string]DisplayMode{"execute-api": DisplayTeamID},
+		Routes:  map[string]DisplayMode{"/franchise/{id}": DisplayAlias},
+		Aliases: map[string]string{"0001": "The Commish"},
+	}
+	request := func(stage, domain string) events.APIGatewayProxyRequest {
+		return events.APIGatewayProxyRequest{
+			RequestContext: events.APIGatewayProxyRequestContext{Stage: stage, DomainName: domain},
+		}
+	}
+
+	assert.Equal(t, DisplayFull, config.policyFor("/", request("prod", "mfl-scoring.timkelsch.com")).Mode)
+	assert.Equal(t, DisplayOwnerFirstName, config.policyFor("/", request("stage", "mfl-scoring.timkelsch.com")).Mode)
+	assert.Equal(t, DisplayTeamID, config.policyFor("/", request("stage", "abc.execute-api.us-east-1.amazonaws.com")).Mode)
+	assert.Equal(t, DisplayAlias, config.policyFor("/franchise/{id}", request("prod", "abc.execute-api.x")).Mode)
+	assert.Equal(t, "The Commish", config.policyFor("/", request("prod", "")).Aliases["0001"])
+}
+
+func TestDefaultDisplayConfigMatchesOriginalBehavior(t *testing.T) {
+	config := defaultDisplayConfig()
+	couth := events.APIGatewayProxyRequest{
+		RequestContext: events.APIGatewayProxyRequestContext{DomainName: "abc.execute-api.us-east-1.amazonaws.com"},
+	}
+
+	assert.Equal(t, DisplayTeamID, config.policyFor("/", couth).Mode)
+	assert.Equal(t, DisplayFull, config.policyFor("/", events.APIGatewayProxyRequest{}).Mode)
+	assert.True(t, strings.HasSuffix(printScoringTable(privateFranchises(), config.policyFor("/", couth)),
+		"There are some weirdos in this league. "))
+}
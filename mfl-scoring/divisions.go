@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+type Divisions struct {
+	Division oneOrMany[Division] `json:"division"`
+}
+
+type Division struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Conference string `json:"conference"`
+}
+
+type Conferences struct {
+	Conference oneOrMany[Conference] `json:"conference"`
+}
+
+type Conference struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type DivisionsResponseV1 struct {
+	SchemaVersion string                `json:"schema_version" description:"Version of this response schema."`
+	Metadata      ResponseMetadataV1    `json:"metadata" description:"Where the standings came from and how fresh they are."`
+	Divisions     []DivisionStandingsV1 `json:"divisions" description:"One championship table per division, in the order MFL lists them."`
+}
+
+// DivisionStandingsV1 is a division's own championship table: its franchises are scored against
+// each other only, so places and points run from 1 to the division's size. The first franchise
+// listed won the division.
+type DivisionStandingsV1 struct {
+	ID         string                `json:"id" description:"MFL division ID."`
+	Name       string                `json:"name" description:"Division name."`
+	Conference string                `json:"conference,omitempty" description:"Conference name. Omitted in leagues without conferences."`
+	Franchises []FranchiseStandingV1 `json:"franchises" description:"The division's franchises in division championship order. rank and the scores are within the division; league_rank and league_total_score are league-wide."`
+}
+
+// awardDivisionTitles marks the franchise at the top of each division's own championship table as
+// its winner and adds the bonus to its league total. Leagues without divisions are left alone.
+func awardDivisionTitles(franchises Franchises, scoring ScoringConfig) {
+	for i := range franchises.Franchise {
+		franchises.Franchise[i].DivisionWinner = false
+		franchises.Franchise[i].DivisionBonus = 0
+	}
+
+	ids, members := divisionMembers(franchises.Franchise)
+	won := map[string]bool{}
+	for _, id := range ids {
+		won[scoreDivision(members[id], scoring).Franchise[0].TeamID] = true
+	}
+
+	for i := range franchises.Franchise {
+		f := &franchises.Franchise[i]
+		if !won[f.TeamID] {
+			continue
+		}
+
+		f.DivisionWinner = true
+		f.DivisionBonus = scoring.DivisionWinnerBonus
+		f.TotalScore += scoring.DivisionWinnerBonus
+		f.TotalScoreString = strconv.FormatFloat(f.TotalScore, 'f', 1, 64)
+	}
+}
+
+// divisionMembers groups the franchises by division ID, with the IDs in the order they first
+// appear. Franchises without a division are left out.
+func divisionMembers(franchises []Franchise) ([]string, map[string][]Franchise) {
+	var ids []string
+	members := map[string][]Franchise{}
+	for _, franchise := range franchises {
+		if franchise.Division == "" {
+			continue
+		}
+		if _, ok := members[franchise.Division]; !ok {
+			ids = append(ids, franchise.Division)
+		}
+		members[franchise.Division] = append(members[franchise.Division], franchise)
+	}
+
+	return ids, members
+}
+
+// scoreDivision scores a division's franchises as if they were the whole league, tiebreakers
+// included. The division bonus isn't part of a division table.
+func scoreDivision(members []Franchise, scoring ScoringConfig) Franchises {
+	division := Franchises{Franchise: make([]Franchise, len(members))}
+	copy(division.Franchise, members)
+	for i := range division.Franchise {
+		division.Franchise[i].DivisionBonus = 0
+	}
+
+	return sortFranchises(scoreChampionship(division, scoring))
+}
+
+// divisionNames maps division IDs to names, with the conference name when there is one.
+func divisionNames(divisions []Division, conferences []Conference) map[string]Division {
+	conferenceNames := map[string]string{}
+	for _, conference := range conferences {
+		conferenceNames[conference.ID] = conference.Name
+	}
+
+	names := map[string]Division{}
+	for _, division := range divisions {
+		division.Conference = conferenceNames[division.Conference]
+		names[division.ID] = division
+	}
+
+	return names
+}
+
+// divisionTables builds each division's championship table, with every franchise's league-wide
+// rank and total alongside. Divisions are in the order MFL lists them; franchises in a division MFL
+// didn't list get a table named after the division ID.
+func divisionTables(standings Standings, policy DisplayPolicy, now time.Time) []DivisionStandingsV1 {
+	names := divisionNames(standings.Divisions, standings.Conferences)
+	leagueRanks := map[string]int{}
+	leagueTotals := map[string]float64{}
+	for i, franchise := range standings.Franchises.Franchise {
+		leagueRanks[franchise.TeamID] = i + 1
+		leagueTotals[franchise.TeamID] = franchise.TotalScore
+	}
+
+	ids, members := divisionMembers(standings.Franchises.Franchise)
+	order := make([]string, 0, len(standings.Divisions)+len(ids))
+	for _, division := range standings.Divisions {
+		order = append(order, division.ID)
+	}
+	for _, id := range ids {
+		if _, ok := names[id]; !ok {
+			order = append(order, id)
+		}
+	}
+
+	tables := make([]DivisionStandingsV1, 0, len(order))
+	for _, id := range order {
+		table := DivisionStandingsV1{ID: id, Name: id, Conference: names[id].Conference,
+			Franchises: []FranchiseStandingV1{}}
+		if division, ok := names[id]; ok {
+			table.Name = division.Name
+		}
+
+		if len(members[id]) > 0 {
+			division := standings
+			division.Franchises = scoreDivision(members[id], standings.Scoring)
+			table.Franchises = newStandingsResponseV1(division, policy, now).Franchises
+		}
+		for i := range table.Franchises {
+			f := &table.Franchises[i]
+			total := leagueTotals[f.FranchiseID]
+			f.LeagueRank = leagueRanks[f.FranchiseID]
+			f.LeagueTotalScore = &total
+		}
+		tables = append(tables, table)
+	}
+
+	return tables
+}
+
+func printDivisionTables(divisions []DivisionStandingsV1) string {
+	tables := make([]string, 0, len(divisions))
+	for _, division := range divisions {
+		title := division.Name
+		if division.Conference != "" {
+			title += " (" + division.Conference + ")"
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(&bytes.Buffer{})
+		t.SetTitle(title)
+		t.AppendHeader(table.Row{"#", "Team", Record, FantasyPts, TotalPts, "League Pts", "League Rank"})
+		for _, f := range division.Franchises {
+			leagueTotal := ""
+			if f.LeagueTotalScore != nil {
+				leagueTotal = formatScore(*f.LeagueTotalScore)
+			}
+			t.AppendRow(table.Row{f.Rank, f.DisplayName, formatRecord(f.Record.Wins, f.Record.Losses, f.Record.Ties),
+				strconv.FormatFloat(f.PointsFor, 'f', 2, 64), formatScore(f.TotalScore), leagueTotal, f.LeagueRank})
+		}
+		tables = append(tables, t.Render())
+	}
+
+	return strings.Join(tables, "\n\n")
+}
+
+func serveDivisions(ctx context.Context, request events.APIGatewayProxyRequest,
+	_ map[string]string) (events.APIGatewayProxyResponse, error) {
+	format, err := jsonOrTextFormat(request)
+	if err != nil {
+		return textResponse(http.StatusNotAcceptable, err.Error()), nil
+	}
+
+	standings, policy, err := standingsForRoute(ctx, "/divisions", request)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	if len(standings.Divisions) == 0 {
+		return textResponse(http.StatusNotFound, "this league has no divisions"), nil
+	}
+
+	now := time.Now()
+	divisions := divisionTables(standings, policy, now)
+
+	if format == FormatJSON {
+		return jsonResponse(http.StatusOK, DivisionsResponseV1{
+			SchemaVersion: ResponseSchemaVersion,
+			Metadata:      newStandingsResponseV1(standings, policy, now).Metadata,
+			Divisions:     divisions,
+		}, "application/json")
+	}
+
+	return textResponse(http.StatusOK, printDivisionTables(divisions)+formatWarnings(standings.Warnings)), nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// divisionStandings is scoredTiedStandings split into two divisions, A and B in East and C and D
+// in West, ranked with the given division winner bonus.
+func divisionStandings(bonus float64) Standings {
+	standings := scoredTiedStandings()
+	for i := range standings.Franchises.Franchise {
+		f := &standings.Franchises.Franchise[i]
+		f.Division = "00"
+		if f.TeamID == "0003" || f.TeamID == "0004" {
+			f.Division = "01"
+		}
+	}
+
+	standings.Scoring = ScoringConfig{DivisionWinnerBonus: bonus}
+	standings.Franchises = rankFranchises(scoreChampionship(standings.Franchises, standings.Scoring), standings.Scoring)
+	standings.Divisions = []Division{{ID: "00", Name: "East", Conference: "00"}, {ID: "01", Name: "West"}}
+	standings.Conferences = []Conference{{ID: "00", Name: "AFC"}}
+
+	return standings
+}
+
+func TestLeagueResponseDivisions(t *testing.T) {
+	testCases := []struct {
+		name      string
+		json      string
+		divisions []Division
+	}{
+		{name: "none", json: `{"league": {"franchises": {"franchise": []}}}`},
+		{name: "one", json: `{"league": {"divisions": {"division": {"id": "00", "name": "East"}}}}`,
+			divisions: []Division{{ID: "00", Name: "East"}}},
+		{name: "many", json: `{"league": {"divisions": {"division": [{"id": "00", "name": "East", "conference": "01"},
+			{"id": "01", "name": "West", "conference": "01"}]}}}`,
+			divisions: []Division{{ID: "00", Name: "East", Conference: "01"}, {ID: "01", Name: "West", Conference: "01"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var response LeagueResponse
+			require.NoError(t, json.Unmarshal([]byte(tc.json), &response))
+			assert.Equal(t, tc.divisions, []Division(response.League.Divisions.Division))
+		})
+	}
+}
+
+func TestAssociateStandingsKeepsDivision(t *testing.T) {
+	league := LeagueResponse{League: League{Franchises: Franchises{Franchise: []Franchise{
+		{TeamID: "0001", TeamName: "A", Division: "01"},
+	}}}}
+	standings := LeagueStandingsResponse{LeagueStandings: LeagueStandings{Franchise: []Franchise{
+		{TeamID: "0001", RecordWinsString: "1", RecordLossesString: "0", RecordTiesString: "0", PointsForString: "10"},
+	}}}
+
+	franchises, err := associateStandingsWithFranchises(league, standings)
+	require.NoError(t, err)
+	assert.Equal(t, "01", franchises.Franchise[0].Division)
+}
+
+func TestRankFranchisesDivisionTitles(t *testing.T) {
+	testCases := []struct {
+		name    string
+		bonus   float64
+		order   []string
+		totals  []float64
+		winners []string
+	}{
+		// Each division is scored on its own. East: A and B both have 3.0, and A has more fantasy points.
+		// West: C and D both have 3.0 and the same fantasy points, and C has the better AllPlay.
+		// Without a bonus the titles are only flags: B 7.0, A 5.0, C 4.0, D 4.0.
+		{name: "no bonus", order: []string{"B", "A", "C", "D"}, totals: []float64{7, 5, 4, 4},
+			winners: []string{"A", "C"}},
+		{name: "bonus", bonus: 1, order: []string{"B", "A", "C", "D"}, totals: []float64{7, 6, 5, 4},
+			winners: []string{"A", "C"}},
+		// A's bonus ties it with B on 7.0, and A has more fantasy points.
+		{name: "bonus reorders", bonus: 2, order: []string{"A", "B", "C", "D"}, totals: []float64{7, 7, 6, 4},
+			winners: []string{"A", "C"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var order, winners []string
+			var totals []float64
+			for _, f := range divisionStandings(tc.bonus).Franchises.Franchise {
+				order = append(order, f.TeamName)
+				totals = append(totals, f.TotalScore)
+				if f.DivisionWinner {
+					winners = append(winners, f.TeamName)
+					assert.Equal(t, tc.bonus, f.DivisionBonus)
+				}
+			}
+			assert.Equal(t, tc.order, order)
+			assert.Equal(t, tc.totals, totals)
+			assert.Equal(t, tc.winners, winners)
+		})
+	}
+}
+
+func TestRankFranchisesWithoutDivisions(t *testing.T) {
+	standings := scoredTiedStandings()
+	ranked := rankFranchises(standings.Franchises, ScoringConfig{DivisionWinnerBonus: 3})
+
+	for _, f := range ranked.Franchise {
+		assert.False(t, f.DivisionWinner)
+	}
+	assert.Equal(t, 7.0, ranked.Franchise[0].TotalScore)
+}
+
+func TestDivisionTables(t *testing.T) {
+	standings := divisionStandings(2)
+	response := newStandingsResponseV1(standings, DisplayPolicy{Mode: DisplayFull}, standings.FetchedAt)
+
+	assert.Equal(t, "East", response.Franchises[0].Division)
+	assert.True(t, response.Franchises[0].DivisionWinner)
+	assert.Equal(t, 2.0, response.Franchises[0].DivisionBonus)
+	assert.Zero(t, response.Franchises[0].LeagueRank, "league ranks are only set in division tables")
+
+	divisions := divisionTables(standings, DisplayPolicy{Mode: DisplayFull}, standings.FetchedAt)
+	require.Len(t, divisions, 2)
+	assert.Equal(t, "AFC", divisions[0].Conference)
+
+	east := divisions[0].Franchises
+	require.Len(t, east, 2)
+	assert.Equal(t, []string{"A", "B"}, []string{east[0].DisplayName, east[1].DisplayName})
+	assert.Equal(t, []int{1, 2}, []int{east[0].Rank, east[1].Rank})
+	assert.Equal(t, []float64{2, 1}, []float64{east[0].PointsScore, east[1].PointsScore}, "places out of 2")
+	assert.Equal(t, []float64{3, 3}, []float64{east[0].TotalScore, east[1].TotalScore})
+	assert.Zero(t, east[0].DivisionBonus, "the bonus isn't part of the division table")
+	assert.True(t, east[0].DivisionWinner)
+	assert.Equal(t, []int{1, 2}, []int{east[0].LeagueRank, east[1].LeagueRank})
+	assert.Equal(t, 7.0, *east[0].LeagueTotalScore)
+
+	west := divisions[1].Franchises
+	assert.Equal(t, []string{"C", "D"}, []string{west[0].DisplayName, west[1].DisplayName})
+	assert.Equal(t, []int{3, 4}, []int{west[0].LeagueRank, west[1].LeagueRank})
+
+	text := printDivisionTables(divisions)
+	assert.Contains(t, text, "East (AFC)")
+	assert.Contains(t, text, "West")
+	assert.Contains(t, text, "LEAGUE PTS")
+}
+
+func TestDivisionTablesUnlistedDivision(t *testing.T) {
+	standings := Standings{Franchises: Franchises{Franchise: []Franchise{
+		{TeamID: "0001", Division: "07", PointsFor: 20}, {TeamID: "0002", PointsFor: 10},
+	}}}
+
+	divisions := divisionTables(standings, DisplayPolicy{Mode: DisplayFull}, time.Now())
+
+	require.Len(t, divisions, 1)
+	assert.Equal(t, "07", divisions[0].Name)
+	require.Len(t, divisions[0].Franchises, 1)
+	assert.Equal(t, 1, divisions[0].Franchises[0].LeagueRank)
+}
+
+func TestExplainDivisionBonus(t *testing.T) {
+	explanations := explainStandings(divisionStandings(2), DisplayPolicy{Mode: DisplayFull})
+
+	assert.Equal(t, 2.0, explanations[0].DivisionBonus)
+	assert.Contains(t, explanations[0].Narrative, "A won the division, worth a 2.0 point bonus.")
+	assert.Contains(t, explanations[0].Narrative, "4.0 + 1.0 + 2.0 = 7.0 championship points, 1st of 4.")
+	assert.Zero(t, explanations[1].DivisionBonus)
+}
+
+func TestProjectScenariosDivisionBonus(t *testing.T) {
+	standings := divisionStandings(2)
+	franchises := applyDisplayPolicy(standings.Franchises, DisplayPolicy{Mode: DisplayFull}).Franchise
+	games := []Game{{Week: 4, Home: "0002", Away: "0001"}, {Week: 4, Home: "0003", Away: "0004"}}
+
+	withBonus := projectScenarios(franchises, games, standings.Scoring)
+	withoutBonus := projectScenarios(franchises, games, ScoringConfig{})
+	for i := range withBonus {
+		assert.Equal(t, withoutBonus[i].BestCase+2, withBonus[i].BestCase, withBonus[i].DisplayName)
+		assert.Equal(t, withoutBonus[i].WorstCase, withBonus[i].WorstCase, withBonus[i].DisplayName)
+	}
+
+	over := projectScenarios(franchises, nil, standings.Scoring)
+	for _, scenario := range over {
+		assert.Equal(t, scenario.TotalScore, scenario.BestCase, scenario.DisplayName)
+		assert.Equal(t, scenario.TotalScore, scenario.WorstCase, scenario.DisplayName)
+	}
+}
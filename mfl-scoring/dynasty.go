@@ -0,0 +1,361 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+type DynastyResponseV1 struct {
+	SchemaVersion string               `json:"schema_version" description:"Version of this response schema."`
+	Metadata      ResponseMetadataV1   `json:"metadata" description:"Where the current standings came from and how fresh they are."`
+	Seasons       []int                `json:"seasons" description:"Seasons counted, oldest first."`
+	Franchises    []DynastyFranchiseV1 `json:"franchises" description:"Franchises from the most championship points across all seasons to the fewest."`
+}
+
+// DynastyFranchiseV1 is a franchise's championship points added up across seasons. Owners in the
+// owner registry are followed from franchise to franchise; anyone else is followed by MFL franchise
+// ID, so a renamed team keeps its history.
+type DynastyFranchiseV1 struct {
+	Rank          int               `json:"rank" description:"Position on the leaderboard, 1 is first."`
+	FranchiseID   string            `json:"franchise_id" description:"MFL franchise ID in the latest season, e.g. 0003."`
+	OwnerID       string            `json:"owner_id,omitempty" description:"Owner registry identity the points were added up under. Omitted when the display policy hides owners or the registry doesn't cover the franchise."`
+	DisplayName   string            `json:"display_name" description:"Label for the franchise in its latest season."`
+	FormerNames   []string          `json:"former_names,omitempty" description:"Earlier labels for the franchise, newest first. Omitted when it never changed."`
+	TotalScore    float64           `json:"total_score" description:"Championship points summed over every season."`
+	Championships int               `json:"championships" description:"Seasons the franchise finished first."`
+	Seasons       []DynastySeasonV1 `json:"seasons" description:"The franchise's finish in each season it played, oldest first."`
+}
+
+type DynastySeasonV1 struct {
+	Year        int     `json:"year" description:"Season."`
+	FranchiseID string  `json:"franchise_id" description:"MFL franchise ID that season."`
+	Rank        int     `json:"rank" description:"Championship position that season."`
+	DisplayName string  `json:"display_name" description:"Label for the franchise that season."`
+	TotalScore  float64 `json:"total_score" description:"Championship points that season."`
+}
+
+// historySeason is an earlier season of the league as listed in League.History. Leagues can be
+// renumbered and move servers between seasons, so each season keeps its own host and league ID.
+type historySeason struct {
+	Year     int
+	BaseURL  string
+	LeagueID string
+}
+
+// scoredSeason is one season's championship table in finishing order.
+type scoredSeason struct {
+	Year       int
+	Franchises []Franchise
+}
+
+// historySeasons reads the seasons before currentYear from the league history, oldest first. MFL
+// history URLs look like https://www46.myfantasyleague.com/2019/home/15781. Entries that can't be
+// read are skipped with a warning rather than failing the leaderboard, and so are entries on other
+// hosts, since every export request carries the API key.
+func historySeasons(history History, currentYear int) ([]historySeason, []string) {
+	var seasons []historySeason
+	var warnings []string
+	seen := map[int]bool{}
+	for _, entry := range history.League {
+		year, err := convertStringToInteger(entry.Year)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Skipped league history entry with year %q.", entry.Year))
+			continue
+		}
+		if year >= currentYear || seen[year] {
+			continue
+		}
+
+		parsed, err := url.Parse(entry.URL)
+		var segments []string
+		if err == nil {
+			segments = splitPath(parsed.Path)
+		}
+		if len(segments) < 3 || parsed.Host == "" || segments[0] != entry.Year {
+			warnings = append(warnings, fmt.Sprintf("Skipped the %d season: unrecognised history URL %q.", year,
+				entry.URL))
+			continue
+		}
+
+		if !isMFLHost(parsed.Hostname()) {
+			warnings = append(warnings, fmt.Sprintf("Skipped the %d season: %s isn't a MyFantasyLeague server.", year,
+				parsed.Hostname()))
+			continue
+		}
+
+		seen[year] = true
+		seasons = append(seasons, historySeason{
+			Year:     year,
+			BaseURL:  parsed.Scheme + "://" + parsed.Host + "/",
+			LeagueID: segments[len(segments)-1],
+		})
+	}
+
+	sort.Slice(seasons, func(i, j int) bool { return seasons[i].Year < seasons[j].Year })
+
+	return seasons, warnings
+}
+
+// isMFLHost reports whether the host is one of MyFantasyLeague's servers.
+func isMFLHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "myfantasyleague.com" || strings.HasSuffix(host, ".myfantasyleague.com")
+}
+
+// exportURL is the export API URL for the season, the historical counterpart of leagueAPIURL.
+func (s historySeason) exportURL(query, apiKey string) string {
+	return s.BaseURL + strconv.Itoa(s.Year) + "/" + LeagueAPIPath + query + "&L=" + s.LeagueID + "&" +
+		APIOutputTypeQuery + "&APIKEY=" + apiKey
+}
+
+// scoreHistorySeason fetches an earlier season and scores it under the current rules, with the owners
+// the registry has for that season. AllPlay isn't scraped for old seasons, so ties on total points
+// are only broken by fantasy points.
+func scoreHistorySeason(client HTTPClient, apiKey string, season historySeason, scoring ScoringConfig,
+	owners []OwnerConfig) (scoredSeason, error) {
+	details, err := getFranchiseDetails(client, season.exportURL(LeagueAPIQuery, apiKey))
+	if err != nil {
+		return scoredSeason{}, err
+	}
+
+	leagueStandings, err := getLeagueStandings(client, season.exportURL(LeagueStandingsAPIQuery, apiKey))
+	if err != nil {
+		return scoredSeason{}, err
+	}
+
+	franchises, err := associateStandingsWithFranchises(details, leagueStandings)
+	if err != nil {
+		return scoredSeason{}, err
+	}
+	franchises = applyOwnerRegistry(franchises, owners, season.Year)
+
+	scoring.Format = scoring.resolveFormat(detectLeagueFormat(details.League, leagueStandings.LeagueStandings))
+	if scoring.Format == LeagueHeadToHead {
+		franchises = populateHeadToHeadRecords(franchises)
+	}
+	if scoring.Format == LeaguePointsOnly {
+		scoring.Median = MedianOff
+	}
+	if scoring.scoresMedian() {
+		schedule, err := getSchedule(client, season.exportURL(ScheduleAPIQuery, apiKey))
+		if err != nil {
+			return scoredSeason{}, err
+		}
+		games, err := scheduleGames(schedule)
+		if err != nil {
+			return scoredSeason{}, err
+		}
+		franchises = applyMedianRecords(franchises, games)
+	}
+
+	ranked := rankFranchises(scoreChampionship(franchises, scoring), scoring)
+
+	return scoredSeason{Year: season.Year, Franchises: ranked.Franchise}, nil
+}
+
+// fetchHistorySeasons scores every earlier season concurrently. A season that can't be fetched is
+// left out with a warning.
+func fetchHistorySeasons(ctx context.Context, standings Standings,
+	owners []OwnerConfig) ([]scoredSeason, []string, error) {
+	apiKey, err := getAPIKey(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	currentYear, _ := convertStringToInteger(LeagueYear) //nolint:errcheck // LeagueYear is a constant.
+	seasons, warnings := historySeasons(standings.History, currentYear)
+
+	scored := make([]scoredSeason, len(seasons))
+	errs := make([]error, len(seasons))
+	var wg sync.WaitGroup
+	for i, season := range seasons {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, span := startSpan(ctx, "fetch.history", slog.Int("year", season.Year))
+			fetchStart := time.Now()
+			scored[i], errs[i] = scoreHistorySeason(&http.Client{}, apiKey, season, standings.Scoring, owners)
+			logUpstream(ctx, "history", fetchStart, errs[i])
+			span.End(errs[i])
+		}()
+	}
+	wg.Wait()
+
+	var fetched []scoredSeason
+	for i, season := range seasons {
+		if errs[i] != nil {
+			telemetryFromContext(ctx).Count(MetricUpstreamErrors, 1)
+			warnings = append(warnings, missingSeasonWarning(season.Year, errs[i]))
+			continue
+		}
+		fetched = append(fetched, scored[i])
+	}
+
+	return fetched, warnings, nil
+}
+
+// missingSeasonWarning explains a season that couldn't be fetched. Warnings are public, and a failed
+// export request's error includes its URL, so the API key is taken out.
+func missingSeasonWarning(year int, err error) string {
+	return fmt.Sprintf("The %d season is missing: %s", year, redactError(err))
+}
+
+// dynastyLeaderboard adds up each owner's or franchise's championship points over the seasons,
+// which must be oldest first. The latest season's label names the entry and earlier ones are kept as
+// former names. Ties on points go to the entry with more championships.
+func dynastyLeaderboard(seasons []scoredSeason, policy DisplayPolicy) []DynastyFranchiseV1 {
+	byKey := map[string]*DynastyFranchiseV1{}
+	var order []string
+	for _, season := range seasons {
+		franchises := applyDisplayPolicy(Franchises{Franchise: season.Franchises}, policy).Franchise
+		for i, franchise := range franchises {
+			// The key comes from the unredacted franchise so owners are followed even when the
+			// display policy hides who they are.
+			key := "franchise:" + season.Franchises[i].TeamID
+			if ownerID := season.Franchises[i].OwnerID; ownerID != "" {
+				key = "owner:" + ownerID
+			}
+			entry, ok := byKey[key]
+			if !ok {
+				entry = &DynastyFranchiseV1{}
+				byKey[key] = entry
+				order = append(order, key)
+			}
+			entry.FranchiseID = franchise.TeamID
+			entry.OwnerID = franchise.OwnerID
+
+			label := displayLabel(franchise)
+			if entry.DisplayName != "" && entry.DisplayName != label {
+				entry.FormerNames = append([]string{entry.DisplayName}, entry.FormerNames...)
+			}
+			entry.DisplayName = label
+			entry.TotalScore += franchise.TotalScore
+			if i == 0 {
+				entry.Championships++
+			}
+			entry.Seasons = append(entry.Seasons, DynastySeasonV1{Year: season.Year,
+				FranchiseID: franchise.TeamID, Rank: i + 1, DisplayName: label, TotalScore: franchise.TotalScore})
+		}
+	}
+
+	leaderboard := make([]DynastyFranchiseV1, 0, len(order))
+	for _, key := range order {
+		entry := byKey[key]
+		entry.TotalScore = roundFloat(entry.TotalScore, 1)
+		entry.FormerNames = uniqueNames(entry.FormerNames, entry.DisplayName)
+		leaderboard = append(leaderboard, *entry)
+	}
+
+	sort.SliceStable(leaderboard, func(i, j int) bool {
+		if leaderboard[i].TotalScore != leaderboard[j].TotalScore {
+			return leaderboard[i].TotalScore > leaderboard[j].TotalScore
+		}
+		return leaderboard[i].Championships > leaderboard[j].Championships
+	})
+	for i := range leaderboard {
+		leaderboard[i].Rank = i + 1
+	}
+
+	return leaderboard
+}
+
+// uniqueNames drops repeats and the current name, so a team that changed its name and back only
+// lists each name once.
+func uniqueNames(names []string, current string) []string {
+	var unique []string
+	seen := map[string]bool{current: true}
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+
+	return unique
+}
+
+func printDynastyTable(seasons []int, leaderboard []DynastyFranchiseV1) string {
+	t := table.NewWriter()
+	t.SetOutputMirror(&bytes.Buffer{})
+
+	header := table.Row{"Rank", "Team", "Total Pts", "Titles"}
+	for _, year := range seasons {
+		header = append(header, year)
+	}
+	t.AppendHeader(header)
+
+	for _, franchise := range leaderboard {
+		results := map[int]DynastySeasonV1{}
+		for _, season := range franchise.Seasons {
+			results[season.Year] = season
+		}
+
+		row := table.Row{franchise.Rank, franchise.DisplayName, formatScore(franchise.TotalScore),
+			franchise.Championships}
+		for _, year := range seasons {
+			if season, ok := results[year]; ok {
+				row = append(row, formatScore(season.TotalScore)+" ("+ordinal(season.Rank)+")")
+			} else {
+				row = append(row, "")
+			}
+		}
+		t.AppendRow(row)
+	}
+
+	return t.Render()
+}
+
+func serveDynasty(ctx context.Context, request events.APIGatewayProxyRequest,
+	_ map[string]string) (events.APIGatewayProxyResponse, error) {
+	format, err := jsonOrTextFormat(request)
+	if err != nil {
+		return textResponse(http.StatusNotAcceptable, err.Error()), nil
+	}
+
+	standings, policy, err := standingsForRoute(ctx, "/dynasty", request)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	config, err := currentConfig()
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	seasons, warnings, err := fetchHistorySeasons(ctx, standings, config.Owners)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+	currentYear, _ := convertStringToInteger(LeagueYear) //nolint:errcheck // LeagueYear is a constant.
+	seasons = append(seasons, scoredSeason{Year: currentYear, Franchises: standings.Franchises.Franchise})
+	standings.Warnings = append(standings.Warnings, warnings...)
+
+	years := make([]int, 0, len(seasons))
+	for _, season := range seasons {
+		years = append(years, season.Year)
+	}
+	leaderboard := dynastyLeaderboard(seasons, policy)
+
+	if format == FormatJSON {
+		return jsonResponse(http.StatusOK, DynastyResponseV1{
+			SchemaVersion: ResponseSchemaVersion,
+			Metadata:      newStandingsResponseV1(standings, policy, time.Now()).Metadata,
+			Seasons:       years,
+			Franchises:    leaderboard,
+		}, "application/json")
+	}
+
+	return textResponse(http.StatusOK, printDynastyTable(years, leaderboard)+formatWarnings(standings.Warnings)), nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// historyClient answers export requests from canned bodies keyed by the TYPE query parameter.
+type historyClient struct {
+	bodies map[string]any
+	urls   []string
+}
+
+func (c *historyClient) Do(req *http.Request) (*http.Response, error) {
+	c.urls = append(c.urls, req.URL.String())
+	body, ok := c.bodies[req.URL.Query().Get("TYPE")]
+	if !ok {
+		return nil, errors.New("unexpected request")
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(data))}, nil
+}
+
+func TestHistorySeasons(t *testing.T) {
+	var history History
+	require.NoError(t, json.Unmarshal([]byte(`{"league": [
+		{"url": "https://www46.myfantasyleague.com/2025/home/15781", "year": "2025"},
+		{"url": "https://www46.myfantasyleague.com/2023/home/15781", "year": "2023"},
+		{"url": "http://www12.myfantasyleague.com/2022/home/40210", "year": "2022"},
+		{"url": "https://www46.myfantasyleague.com/2023/home/15781", "year": "2023"},
+		{"url": "not a league", "year": "2021"},
+		{"url": "https://mfl.example.com/2020/home/15781", "year": "2020"},
+		{"url": "https://www46.myfantasyleague.com/home/15781", "year": "twenty"}
+	]}`), &history))
+
+	seasons, warnings := historySeasons(history, 2025)
+
+	assert.Equal(t, []historySeason{
+		{Year: 2022, BaseURL: "http://www12.myfantasyleague.com/", LeagueID: "40210"},
+		{Year: 2023, BaseURL: "https://www46.myfantasyleague.com/", LeagueID: "15781"},
+	}, seasons)
+	assert.Equal(t, []string{
+		`Skipped the 2021 season: unrecognised history URL "not a league".`,
+		"Skipped the 2020 season: mfl.example.com isn't a MyFantasyLeague server.",
+		`Skipped league history entry with year "twenty".`,
+	}, warnings)
+}
+
+func TestIsMFLHost(t *testing.T) {
+	assert.True(t, isMFLHost("www46.myfantasyleague.com"))
+	assert.True(t, isMFLHost("API.MyFantasyLeague.com"))
+	assert.True(t, isMFLHost("myfantasyleague.com"))
+	assert.False(t, isMFLHost("myfantasyleague.com.example.com"))
+	assert.False(t, isMFLHost("evilmyfantasyleague.com"))
+}
+
+func TestMissingSeasonWarning(t *testing.T) {
+	exportURL := "http://www12.myfantasyleague.com/2022/export?TYPE=league&L=40210&JSON=1&APIKEY="
+	err := &url.Error{Op: "Get", URL: exportURL + "supersecret", Err: errors.New("i/o timeout")}
+
+	assert.Equal(t, `The 2022 season is missing: Get "`+exportURL+`": i/o timeout`, missingSeasonWarning(2022, err))
+}
+
+func TestScoreHistorySeason(t *testing.T) {
+	client := &historyClient{bodies: map[string]any{
+		"league": LeagueResponse{League: League{H2H: "YES", Franchises: Franchises{Franchise: []Franchise{
+			{TeamID: "0001", TeamName: "Old Name", OwnerName: Team1Owner},
+			{TeamID: "0002", TeamName: Team2Name, OwnerName: Team2Owner},
+		}}}},
+		"leagueStandings": LeagueStandingsResponse{LeagueStandings: LeagueStandings{Franchise: []Franchise{
+			{TeamID: "0001", RecordWinsString: "4", RecordLossesString: "9", RecordTiesString: "0",
+				PointsForString: "1100"},
+			{TeamID: "0002", RecordWinsString: "9", RecordLossesString: "4", RecordTiesString: "0",
+				PointsForString: "1300"},
+		}}},
+	}}
+	season := historySeason{Year: 2022, BaseURL: "http://www12.myfantasyleague.com/", LeagueID: "40210"}
+
+	owners := []OwnerConfig{{ID: "first", Name: "First Owner", Franchises: []OwnerTenure{{FranchiseID: "0001", To: 2022}}}}
+
+	scored, err := scoreHistorySeason(client, "secret", season, ScoringConfig{}, owners)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2022, scored.Year)
+	require.Len(t, scored.Franchises, 2)
+	assert.Equal(t, "0002", scored.Franchises[0].TeamID)
+	assert.Equal(t, 4.0, scored.Franchises[0].TotalScore)
+	assert.Equal(t, "http://www12.myfantasyleague.com/2022/export?TYPE=league&L=40210&JSON=1&APIKEY=secret",
+		client.urls[0])
+	assert.Equal(t, "First Owner", scored.Franchises[1].OwnerName, "the registry owner for that season")
+
+	_, err = scoreHistorySeason(&historyClient{}, "secret", season, ScoringConfig{}, nil)
+	assert.Error(t, err)
+}
+
+func TestDynastyLeaderboard(t *testing.T) {
+	seasons := []scoredSeason{
+		{Year: 2023, Franchises: []Franchise{
+			{TeamID: "0002", TeamName: "Early Name", TotalScore: 4},
+			{TeamID: "0001", TeamName: Team1Name, TotalScore: 2},
+		}},
+		{Year: 2024, Franchises: []Franchise{
+			{TeamID: "0001", TeamName: Team1Name, TotalScore: 3.5},
+			{TeamID: "0002", TeamName: "Middle Name", TotalScore: 2.5},
+		}},
+		{Year: 2025, Franchises: []Franchise{
+			{TeamID: "0001", TeamName: Team1Name, TotalScore: 3},
+			{TeamID: "0002", TeamName: Team2Name, TotalScore: 2},
+		}},
+	}
+
+	leaderboard := dynastyLeaderboard(seasons, DisplayPolicy{Mode: DisplayFull})
+
+	require.Len(t, leaderboard, 2)
+	first, second := leaderboard[0], leaderboard[1]
+	assert.Equal(t, "0001", first.FranchiseID, "level on points, more titles")
+	assert.Equal(t, 8.5, first.TotalScore)
+	assert.Equal(t, 2, first.Championships)
+	assert.Nil(t, first.FormerNames)
+	assert.Equal(t, 8.5, second.TotalScore)
+	assert.Equal(t, 2, second.Rank)
+	assert.Equal(t, Team2Name, second.DisplayName)
+	assert.Equal(t, []string{"Middle Name", "Early Name"}, second.FormerNames)
+	assert.Equal(t, DynastySeasonV1{Year: 2023, FranchiseID: "0002", Rank: 1, DisplayName: "Early Name", TotalScore: 4},
+		second.Seasons[0])
+
+	hidden := dynastyLeaderboard(seasons, DisplayPolicy{Mode: DisplayTeamID})
+	assert.Equal(t, "0002", hidden[1].DisplayName)
+	assert.Nil(t, hidden[1].FormerNames, "renames can't leak through team IDs")
+}
+
+func TestDynastyLeaderboardFollowsOwners(t *testing.T) {
+	// The owner of 0002 took over 0003 in 2025, and 0002 went to a new owner.
+	seasons := []scoredSeason{
+		{Year: 2024, Franchises: []Franchise{
+			{TeamID: "0002", TeamName: "Old Team", OwnerID: "mover", TotalScore: 3},
+			{TeamID: "0003", TeamName: "Other Team", TotalScore: 2},
+			{TeamID: "0001", TeamName: Team1Name, TotalScore: 1},
+		}},
+		{Year: 2025, Franchises: []Franchise{
+			{TeamID: "0003", TeamName: "New Team", OwnerID: "mover", TotalScore: 3},
+			{TeamID: "0001", TeamName: Team1Name, TotalScore: 2},
+			{TeamID: "0002", TeamName: "Old Team", OwnerID: "newcomer", TotalScore: 1},
+		}},
+	}
+
+	leaderboard := dynastyLeaderboard(seasons, DisplayPolicy{Mode: DisplayFull})
+
+	require.Len(t, leaderboard, 4)
+	assert.Equal(t, "mover", leaderboard[0].OwnerID)
+	assert.Equal(t, "0003", leaderboard[0].FranchiseID)
+	assert.Equal(t, 6.0, leaderboard[0].TotalScore)
+	assert.Equal(t, []string{"Old Team"}, leaderboard[0].FormerNames)
+	assert.Equal(t, 2, leaderboard[0].Championships)
+	assert.Equal(t, "newcomer", leaderboard[3].OwnerID)
+	assert.Equal(t, 1.0, leaderboard[3].TotalScore)
+
+	hidden := dynastyLeaderboard(seasons, DisplayPolicy{Mode: DisplayTeamID})
+	assert.Equal(t, 6.0, hidden[0].TotalScore, "owners are still followed")
+	assert.Empty(t, hidden[0].OwnerID)
+}
+
+func TestPrintDynastyTable(t *testing.T) {
+	leaderboard := dynastyLeaderboard([]scoredSeason{
+		{Year: 2024, Franchises: []Franchise{{TeamID: "0001", TeamName: Team1Name, TotalScore: 2}}},
+		{Year: 2025, Franchises: []Franchise{
+			{TeamID: "0002", TeamName: Team2Name, TotalScore: 2},
+			{TeamID: "0001", TeamName: Team1Name, TotalScore: 1},
+		}},
+	}, DisplayPolicy{Mode: DisplayFull})
+
+	table := printDynastyTable([]int{2024, 2025}, leaderboard)
+
+	assert.Contains(t, table, "TITLES")
+	assert.Contains(t, table, "2.0 (1st)")
+	assert.Contains(t, table, "1.0 (2nd)")
+}
+
+func TestServeDynastyRejectsUnsupportedFormatBeforeFetching(t *testing.T) {
+	response, err := serveDynasty(context.Background(), events.APIGatewayProxyRequest{
+		QueryStringParameters: map[string]string{"output": "csv"},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotAcceptable, response.StatusCode)
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const (
+	TiebreakerPointsFor         string = "points_for"
+	TiebreakerAllPlayPercentage string = "all_play_percentage"
+	TiebreakerUnresolved        string = "unresolved"
+)
+
+// FranchiseExplanationV1 breaks a franchise's championship score down into the pieces owners ask
+// about: where they placed in each category, who they tied with and how the tied places were split.
+type FranchiseExplanationV1 struct {
+	Rank          int                 `json:"rank" description:"Championship position, 1 is first."`
+	FranchiseID   string              `json:"franchise_id" description:"MFL franchise ID, e.g. 0003."`
+	DisplayName   string              `json:"display_name" description:"Label to show for the franchise under the display policy."`
+	TotalScore    float64             `json:"total_score" description:"Sum of all championship point components."`
+	PointsFor     ScoreComponentV1    `json:"points_for" description:"How the points score was awarded."`
+	Record        ScoreComponentV1    `json:"record" description:"How the record score was awarded. In points-only leagues the place and score are 0 and display says records aren't scored."`
+	Components    []ScoreComponentV1  `json:"components,omitempty" description:"How the optional components the league scores were awarded."`
+	DivisionBonus float64             `json:"division_bonus,omitempty" description:"Championship points for winning the division. Omitted when none were awarded."`
+	Tiebreaker    *TiebreakerV1       `json:"tiebreaker,omitempty" description:"How a tie on total score was broken. Omitted when there was no tie."`
+	Narrative     []string            `json:"narrative" description:"The breakdown in plain sentences."`
+	Metadata      *ResponseMetadataV1 `json:"metadata,omitempty" description:"Response metadata. Only set on /franchise/{id}."`
+}
+
+// ScoreComponentV1 describes one category that awards championship points by place.
+type ScoreComponentV1 struct {
+	Metric         string    `json:"metric,omitempty" description:"Which optional component this is: points_against, potential_points or lineup_efficiency."`
+	Value          float64   `json:"value" description:"Fantasy points, or the record ranked by the league's record metric (wins plus half a win per tie by default, negative games back for games_back)."`
+	Display        string    `json:"display" description:"Value as shown in the standings table."`
+	Place          int       `json:"place" description:"Best place held in the category, 1 is first."`
+	TiedWith       []string  `json:"tied_with" description:"Display names of franchises with the same value."`
+	Places         []int     `json:"places" description:"Places shared by the tied franchises."`
+	PointsPerPlace []float64 `json:"points_per_place" description:"Championship points each shared place is worth."`
+	Score          float64   `json:"score" description:"Points awarded: the shared places' points divided evenly."`
+}
+
+type TiebreakerV1 struct {
+	TiedWith  []string `json:"tied_with" description:"Display names of franchises with the same total score."`
+	DecidedBy string   `json:"decided_by" description:"points_for, all_play_percentage, or unresolved when every tiebreaker was equal."`
+}
+
+// ExplanationsResponseV1 is the ?explain=1 document: every franchise's breakdown in championship
+// order.
+type ExplanationsResponseV1 struct {
+	SchemaVersion string                   `json:"schema_version" description:"Version of this response schema."`
+	Metadata      ResponseMetadataV1       `json:"metadata" description:"Where the standings came from and how fresh they are."`
+	Franchises    []FranchiseExplanationV1 `json:"franchises" description:"Breakdowns in championship order."`
+}
+
+// explainStandings explains every franchise. Names come from the policy-redacted copy, so tied
+// franchises are referred to the same way the standings table shows them.
+func explainStandings(standings Standings, policy DisplayPolicy) []FranchiseExplanationV1 {
+	franchises := applyDisplayPolicy(standings.Franchises, policy).Franchise
+
+	pointsFor := func(f Franchise) float64 { return f.PointsFor }
+	recordMagic := func(f Franchise) float64 { return f.RecordMagic }
+
+	explanations := make([]FranchiseExplanationV1, 0, len(franchises))
+	for i, franchise := range franchises {
+		explanation := FranchiseExplanationV1{
+			Rank:          i + 1,
+			FranchiseID:   franchise.TeamID,
+			DisplayName:   displayLabel(franchise),
+			TotalScore:    franchise.TotalScore,
+			PointsFor:     explainComponent(franchises, franchise, pointsFor),
+			Tiebreaker:    explainTiebreaker(franchises, franchise),
+			DivisionBonus: franchise.DivisionBonus,
+		}
+		explanation.PointsFor.Display = strconv.FormatFloat(franchise.PointsFor, 'f', 2, 64)
+		explanation.PointsFor.Score = franchise.PointScore
+		if standings.Scoring.Format == LeaguePointsOnly {
+			explanation.Record = unscoredRecord()
+		} else {
+			explanation.Record = explainComponent(franchises, franchise, recordMagic)
+			explanation.Record.Display = describeRecord(franchise, standings.Scoring)
+			explanation.Record.Score = franchise.RecordScore
+		}
+		for _, config := range standings.Scoring.Components {
+			explanation.Components = append(explanation.Components, explainOptionalComponent(franchises, franchise,
+				config))
+		}
+		explanation.Narrative = narrate(explanation, len(franchises))
+
+		explanations = append(explanations, explanation)
+	}
+
+	return explanations
+}
+
+// unscoredRecord stands in for the record component in points-only leagues, so the field keeps its
+// shape. Place 0 marks it as not scored.
+func unscoredRecord() ScoreComponentV1 {
+	return ScoreComponentV1{Display: "records aren't scored in points-only leagues", TiedWith: []string{},
+		Places: []int{}, PointsPerPlace: []float64{}}
+}
+
+// describeRecord is the record the record score was awarded for under the league's median mode,
+// with the value it was ranked by when that isn't a plain count.
+func describeRecord(franchise Franchise, scoring ScoringConfig) string {
+	headToHead := formatRecord(franchise.RecordWins, franchise.RecordLosses, franchise.RecordTies)
+	versusMedian := formatRecord(franchise.MedianWins, franchise.MedianLosses, franchise.MedianTies) +
+		" against the weekly median"
+
+	var record string
+	switch scoring.Median {
+	case MedianReplace:
+		record = versusMedian
+	case MedianAugment:
+		record = headToHead + " head to head and " + versusMedian
+	default:
+		record = headToHead
+	}
+
+	switch scoring.RecordMetric {
+	case RecordWinPercentage, RecordTiesAsLoss:
+		return record + " (" + strconv.FormatFloat(franchise.RecordMagic, 'f', 3, 64) + ")"
+	case RecordGamesBack:
+		if franchise.RecordMagic == 0 {
+			return record + " (the best record)"
+		}
+		return record + " (" + strconv.FormatFloat(-franchise.RecordMagic, 'f', -1, 64) + " games back)"
+	default:
+		return record
+	}
+}
+
+// explainOptionalComponent explains an optional component, ranking the other way round when the
+// lowest value wins.
+func explainOptionalComponent(franchises []Franchise, franchise Franchise, config ComponentConfig) ScoreComponentV1 {
+	value := func(f Franchise) float64 { return componentValue(f, config.Metric) }
+	if config.LowestWins {
+		value = func(f Franchise) float64 { return -componentValue(f, config.Metric) }
+	}
+
+	component := explainComponent(franchises, franchise, value)
+	component.Metric = string(config.Metric)
+	component.Value = componentValue(franchise, config.Metric)
+	component.Score = componentScore(franchise, config.Metric)
+	component.Display = formatPoints(component.Value)
+	if config.Metric == ComponentLineupEfficiency {
+		component.Display = formatEfficiency(component.Value)
+	}
+
+	return component
+}
+
+// explainComponent mirrors calculatePointsScore and calculateRecordScore: place p of n is worth
+// n-p+1 points, and franchises with the same value share the points for the places they cover.
+func explainComponent(franchises []Franchise, franchise Franchise, value func(Franchise) float64) ScoreComponentV1 {
+	mine := value(franchise)
+	component := ScoreComponentV1{Value: mine, TiedWith: []string{}}
+
+	place := 1
+	for _, other := range franchises {
+		switch {
+		case value(other) > mine:
+			place++
+		case value(other) == mine && other.TeamID != franchise.TeamID:
+			component.TiedWith = append(component.TiedWith, displayLabel(other))
+		}
+	}
+
+	component.Place = place
+	for p := place; p <= place+len(component.TiedWith); p++ {
+		component.Places = append(component.Places, p)
+		component.PointsPerPlace = append(component.PointsPerPlace, float64(len(franchises)-p+1))
+	}
+
+	return component
+}
+
+// explainTiebreaker mirrors sortFranchises: total score, then fantasy points, then AllPlay
+// percentage.
+func explainTiebreaker(franchises []Franchise, franchise Franchise) *TiebreakerV1 {
+	var tied []Franchise
+	for _, other := range franchises {
+		if other.TeamID != franchise.TeamID && other.TotalScore == franchise.TotalScore {
+			tied = append(tied, other)
+		}
+	}
+	if len(tied) == 0 {
+		return nil
+	}
+
+	tiebreaker := &TiebreakerV1{DecidedBy: TiebreakerPointsFor}
+	for _, other := range tied {
+		tiebreaker.TiedWith = append(tiebreaker.TiedWith, displayLabel(other))
+		if other.PointsFor != franchise.PointsFor {
+			continue
+		}
+		if other.AllPlayPercentage != franchise.AllPlayPercentage && tiebreaker.DecidedBy != TiebreakerUnresolved {
+			tiebreaker.DecidedBy = TiebreakerAllPlayPercentage
+		} else if other.AllPlayPercentage == franchise.AllPlayPercentage {
+			tiebreaker.DecidedBy = TiebreakerUnresolved
+		}
+	}
+
+	return tiebreaker
+}
+
+func narrate(explanation FranchiseExplanationV1, teams int) []string {
+	name := explanation.DisplayName
+
+	narrative := []string{
+		fmt.Sprintf("%s scored %s fantasy points, %s. %s", name, explanation.PointsFor.Display,
+			describePlace(explanation.PointsFor), describeSplit(explanation.PointsFor)),
+	}
+
+	terms := []string{formatScore(explanation.PointsFor.Score)}
+	if record := explanation.Record; record.Place != 0 {
+		narrative = append(narrative, fmt.Sprintf("%s went %s, %s. %s", name, record.Display,
+			describePlace(record), describeSplit(record)))
+		terms = append(terms, formatScore(record.Score))
+	}
+	for _, component := range explanation.Components {
+		narrative = append(narrative, fmt.Sprintf("%s had %s %s, %s. %s", name, component.Display,
+			componentLabel(ComponentMetric(component.Metric)), describePlace(component), describeSplit(component)))
+		terms = append(terms, formatScore(component.Score))
+	}
+	if explanation.DivisionBonus != 0 {
+		narrative = append(narrative, fmt.Sprintf("%s won the division, worth a %s point bonus.", name,
+			formatScore(explanation.DivisionBonus)))
+		terms = append(terms, formatScore(explanation.DivisionBonus))
+	}
+
+	total := formatScore(explanation.TotalScore) + " championship points"
+	if len(terms) > 1 {
+		total = strings.Join(terms, " + ") + " = " + total
+	}
+	narrative = append(narrative, fmt.Sprintf("%s, %s of %d.", total, ordinal(explanation.Rank), teams))
+
+	if tiebreaker := explanation.Tiebreaker; tiebreaker != nil {
+		var decided string
+		switch tiebreaker.DecidedBy {
+		case TiebreakerPointsFor:
+			decided = "fantasy points decided the order"
+		case TiebreakerAllPlayPercentage:
+			decided = "fantasy points were also level, so AllPlay percentage decided the order"
+		default:
+			decided = "fantasy points and AllPlay percentage were also level, so the order is arbitrary"
+		}
+		narrative = append(narrative, fmt.Sprintf("Tied on total with %s; %s.", joinNames(tiebreaker.TiedWith),
+			decided))
+	}
+
+	return narrative
+}
+
+func describePlace(component ScoreComponentV1) string {
+	if len(component.TiedWith) == 0 {
+		return ordinal(component.Place) + " in the league"
+	}
+
+	return fmt.Sprintf("tied with %s for %s through %s", joinNames(component.TiedWith),
+		ordinal(component.Places[0]), ordinal(component.Places[len(component.Places)-1]))
+}
+
+func describeSplit(component ScoreComponentV1) string {
+	if len(component.TiedWith) == 0 {
+		return fmt.Sprintf("That place is worth %s points.", formatScore(component.Score))
+	}
+
+	parts := make([]string, len(component.PointsPerPlace))
+	total := 0.0
+	for i, points := range component.PointsPerPlace {
+		parts[i] = formatScore(points)
+		total += points
+	}
+
+	return fmt.Sprintf("Those places are worth %s = %s points, split %d ways for %s each.",
+		strings.Join(parts, " + "), formatScore(total), len(component.Places), formatScore(component.Score))
+}
+
+func formatScore(f float64) string {
+	return strconv.FormatFloat(f, 'f', 1, 64)
+}
+
+func joinNames(names []string) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0]
+	default:
+		return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+	}
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+
+	return strconv.Itoa(n) + suffix
+}
+
+// findExplanation accepts the franchise ID as MFL writes it ("0003") or without the padding ("3").
+func findExplanation(explanations []FranchiseExplanationV1, id string) (FranchiseExplanationV1, bool) {
+	if n, err := strconv.Atoi(id); err == nil && n >= 0 {
+		id = fmt.Sprintf("%04d", n)
+	}
+
+	for _, explanation := range explanations {
+		if explanation.FranchiseID == id {
+			return explanation, true
+		}
+	}
+
+	return FranchiseExplanationV1{}, false
+}
+
+func serveFranchise(ctx context.Context, request events.APIGatewayProxyRequest,
+	params map[string]string) (events.APIGatewayProxyResponse, error) {
+	format, err := jsonOrTextFormat(request)
+	if err != nil {
+		return textResponse(http.StatusNotAcceptable, err.Error()), nil
+	}
+
+	standings, policy, err := standingsForRoute(ctx, "/franchise/{id}", request)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	explanation, ok := findExplanation(explainStandings(standings, policy), params["id"])
+	if !ok {
+		return textResponse(http.StatusNotFound, fmt.Sprintf("no franchise with ID %q", params["id"])), nil
+	}
+
+	if format == FormatJSON {
+		metadata := newStandingsResponseV1(standings, policy, time.Now()).Metadata
+		explanation.Metadata = &metadata
+		return jsonResponse(http.StatusOK, explanation, "application/json")
+	}
+
+	return textResponse(http.StatusOK, strings.Join(explanation.Narrative, "\n")), nil
+}
+
+// serveExplanations answers ?explain=1 on the standings route.
+func serveExplanations(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse,
+	error) {
+	format, err := jsonOrTextFormat(request)
+	if err != nil {
+		return textResponse(http.StatusNotAcceptable, err.Error()), nil
+	}
+
+	standings, policy, err := standingsForRoute(ctx, "/", request)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	explanations := explainStandings(standings, policy)
+	if format == FormatJSON {
+		return jsonResponse(http.StatusOK, ExplanationsResponseV1{
+			SchemaVersion: ResponseSchemaVersion,
+			Metadata:      newStandingsResponseV1(standings, policy, time.Now()).Metadata,
+			Franchises:    explanations,
+		}, "application/json")
+	}
+
+	paragraphs := make([]string, 0, len(explanations))
+	for _, explanation := range explanations {
+		paragraphs = append(paragraphs, strings.Join(explanation.Narrative, "\n"))
+	}
+
+	return textResponse(http.StatusOK, strings.Join(paragraphs, "\n\n")+formatWarnings(standings.Warnings)), nil
+}
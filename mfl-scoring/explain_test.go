@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scoredTiedStandings runs four franchises through the real scoring steps. C and D tie on fantasy
+// points, on record and on total score, leaving AllPlay percentage to separate them.
+func scoredTiedStandings() Standings {
+	franchises := Franchises{Franchise: []Franchise{
+		{TeamID: "0001", TeamName: "A", PointsFor: 100, RecordWins: 1, RecordLosses: 2, AllPlayPercentage: 0.7},
+		{TeamID: "0002", TeamName: "B", PointsFor: 90, RecordWins: 3, AllPlayPercentage: 0.8},
+		{TeamID: "0003", TeamName: "C", PointsFor: 80, RecordWins: 2, RecordLosses: 1, AllPlayPercentage: 0.6},
+		{TeamID: "0004", TeamName: "D", PointsFor: 80, RecordWins: 2, RecordLosses: 1, AllPlayPercentage: 0.5},
+	}}
+
+	return Standings{Franchises: sortFranchises(scoreChampionship(franchises, ScoringConfig{}))}
+}
+
+func TestExplainStandings(t *testing.T) {
+	explanations := explainStandings(scoredTiedStandings(), DisplayPolicy{Mode: DisplayFull})
+
+	assert.Equal(t, []string{"B", "A", "C", "D"}, []string{explanations[0].DisplayName,
+		explanations[1].DisplayName, explanations[2].DisplayName, explanations[3].DisplayName})
+
+	c := explanations[2]
+	assert.Equal(t, 3, c.Rank)
+	assert.Equal(t, ScoreComponentV1{Value: 80, Display: "80.00", Place: 3, TiedWith: []string{"D"},
+		Places: []int{3, 4}, PointsPerPlace: []float64{2, 1}, Score: 1.5}, c.PointsFor)
+	assert.Equal(t, ScoreComponentV1{Value: 2, Display: "2-1-0", Place: 2, TiedWith: []string{"D"},
+		Places: []int{2, 3}, PointsPerPlace: []float64{3, 2}, Score: 2.5}, c.Record)
+	assert.Equal(t, &TiebreakerV1{TiedWith: []string{"D"}, DecidedBy: TiebreakerAllPlayPercentage}, c.Tiebreaker)
+	assert.Equal(t, []string{
+		"C scored 80.00 fantasy points, tied with D for 3rd through 4th. " +
+			"Those places are worth 2.0 + 1.0 = 3.0 points, split 2 ways for 1.5 each.",
+		"C went 2-1-0, tied with D for 2nd through 3rd. " +
+			"Those places are worth 3.0 + 2.0 = 5.0 points, split 2 ways for 2.5 each.",
+		"1.5 + 2.5 = 4.0 championship points, 3rd of 4.",
+		"Tied on total with D; fantasy points were also level, so AllPlay percentage decided the order.",
+	}, c.Narrative)
+
+	b := explanations[0]
+	assert.Nil(t, b.Tiebreaker)
+	assert.Equal(t, "B scored 90.00 fantasy points, 2nd in the league. That place is worth 3.0 points.",
+		b.Narrative[0])
+}
+
+func TestExplainStandingsUsesDisplayNames(t *testing.T) {
+	explanations := explainStandings(scoredTiedStandings(), DisplayPolicy{Mode: DisplayTeamID})
+
+	for _, explanation := range explanations {
+		for _, sentence := range explanation.Narrative {
+			assert.NotRegexp(t, `\b[A-D]\b`, sentence)
+		}
+	}
+	assert.Equal(t, []string{"0004"}, explanations[2].PointsFor.TiedWith)
+}
+
+func TestFindExplanation(t *testing.T) {
+	explanations := explainStandings(scoredTiedStandings(), DisplayPolicy{Mode: DisplayFull})
+
+	for _, id := range []string{"0003", "3"} {
+		explanation, ok := findExplanation(explanations, id)
+		assert.True(t, ok, id)
+		assert.Equal(t, "0003", explanation.FranchiseID)
+	}
+
+	_, ok := findExplanation(explanations, "0042")
+	assert.False(t, ok)
+}
+
+func TestOrdinal(t *testing.T) {
+	for n, expected := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th",
+		13: "13th", 21: "21st", 22: "22nd", 101: "101st", 111: "111th"} {
+		assert.Equal(t, expected, ordinal(n))
+	}
+}
+
+func TestServeFranchiseRejectsFormatBeforeFetching(t *testing.T) {
+	response, err := serveFranchise(context.Background(), events.APIGatewayProxyRequest{
+		QueryStringParameters: map[string]string{"output": "html"},
+	}, map[string]string{"id": "0001"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotAcceptable, response.StatusCode)
+}
+
+func TestExplainStandingsPointsOnly(t *testing.T) {
+	standings := scoredTiedStandings()
+	standings.Scoring.Format = LeaguePointsOnly
+	standings.Franchises = sortFranchises(scoreChampionship(standings.Franchises, standings.Scoring))
+
+	explanations := explainStandings(standings, DisplayPolicy{Mode: DisplayFull})
+
+	assert.Equal(t, "A", explanations[0].DisplayName)
+	assert.Equal(t, unscoredRecord(), explanations[0].Record)
+	data, err := json.Marshal(explanations[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"record":{"value":0,"display":"records aren't scored in points-only leagues"`)
+	assert.Equal(t, []string{
+		"A scored 100.00 fantasy points, 1st in the league. That place is worth 4.0 points.",
+		"4.0 championship points, 1st of 4.",
+	}, explanations[0].Narrative)
+}
+
+func TestDescribeRecordMetric(t *testing.T) {
+	franchise := Franchise{RecordWins: 5, RecordLosses: 3, RecordMagic: 0.625}
+	assert.Equal(t, "5-3-0 (0.625)", describeRecord(franchise, ScoringConfig{RecordMetric: RecordWinPercentage}))
+
+	franchise.RecordMagic = -1.5
+	assert.Equal(t, "5-3-0 (1.5 games back)", describeRecord(franchise, ScoringConfig{RecordMetric: RecordGamesBack}))
+
+	franchise.RecordMagic = 0
+	assert.Equal(t, "5-3-0 (the best record)", describeRecord(franchise, ScoringConfig{RecordMetric: RecordGamesBack}))
+}
@@ -5,6 +5,7 @@ go 1.22.5
 require (
 	github.com/PuerkitoBio/goquery v1.8.1
 	github.com/aws/aws-lambda-go v1.41.0
+	github.com/aws/aws-sdk-go v1.46.7
 	github.com/aws/aws-secretsmanager-caching-go v1.1.2
 	github.com/gocolly/colly v1.2.0
 	golang.org/x/net v0.23.0
@@ -15,7 +16,6 @@ require (
 	github.com/antchfx/htmlquery v1.3.0 // indirect
 	github.com/antchfx/xmlquery v1.3.18 // indirect
 	github.com/antchfx/xpath v1.2.5 // indirect
-	github.com/aws/aws-sdk-go v1.46.7 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/gobwas/glob v0.2.3 // indirect
 	github.com/golang/groupcache v0.0.0-20210331224755-41bb18bfe9da // indirect
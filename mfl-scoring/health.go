@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const (
+	HealthStatusOK      string = "ok"
+	HealthStatusFail    string = "fail"
+	HealthStatusSkipped string = "skipped"
+
+	HealthCheckSecret          string = "secret"
+	HealthCheckLeague          string = "league_api"
+	HealthCheckLeagueStandings string = "league_standings_api"
+	HealthCheckAllPlayTable    string = "allplay_table"
+
+	healthCheckTimeout = 4 * time.Second
+)
+
+var errNoAPIKey = errors.New("API key unavailable")
+
+type HealthCheckResult struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	DurationMS int64  `json:"duration_ms"`
+	Detail     string `json:"detail,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type HealthReport struct {
+	Status    string              `json:"status"`
+	CheckedAt time.Time           `json:"checked_at"`
+	LeagueID  string              `json:"league_id"`
+	Year      string              `json:"year"`
+	Checks    []HealthCheckResult `json:"checks"`
+}
+
+type healthDependencies struct {
+	apiKey  func(ctx context.Context) (string, error)
+	client  HTTPClient
+	baseURL string
+}
+
+func serveHealth(ctx context.Context, _ events.APIGatewayProxyRequest,
+	_ map[string]string) (events.APIGatewayProxyResponse, error) {
+	report := runHealthChecks(ctx, healthDependencies{apiKey: getAPIKey, client: &http.Client{}, baseURL: MflURL})
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	statusCode := http.StatusOK
+	if report.Status != HealthStatusOK {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	return events.APIGatewayProxyResponse{
+		Headers:    map[string]string{"content-type": "application/json", "cache-control": "no-store"},
+		Body:       string(body),
+		StatusCode: statusCode,
+	}, nil
+}
+
+// runHealthChecks retrieves the secret first, since the API checks need it, then probes each MFL
+// endpoint in parallel. A skipped check counts as a failure for the overall status.
+func runHealthChecks(ctx context.Context, deps healthDependencies) HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	var apiKey string
+	secretResult := runHealthCheck(ctx, HealthCheckSecret, func(ctx context.Context) (string, error) {
+		var err error
+		apiKey, err = deps.apiKey(ctx)
+		return "", err
+	})
+
+	checks := []struct {
+		name string
+		run  func(ctx context.Context) (string, error)
+	}{
+		{name: HealthCheckLeague, run: func(_ context.Context) (string, error) {
+			if apiKey == "" {
+				return "", errNoAPIKey
+			}
+			response, err := getFranchiseDetails(deps.client, leagueAPIURL(deps.baseURL, apiKey))
+			if err != nil {
+				return "", err
+			}
+			return countDetail(len(response.League.Franchises.Franchise), "franchises")
+		}},
+		{name: HealthCheckLeagueStandings, run: func(_ context.Context) (string, error) {
+			if apiKey == "" {
+				return "", errNoAPIKey
+			}
+			response, err := getLeagueStandings(deps.client, leagueStandingsAPIURL(deps.baseURL, apiKey))
+			if err != nil {
+				return "", err
+			}
+			return countDetail(len(response.LeagueStandings.Franchise), "franchises")
+		}},
+		{name: HealthCheckAllPlayTable, run: func(ctx context.Context) (string, error) {
+			rows, err := probeAllPlayTable(ctx, deps.client, allPlayPageURL(deps.baseURL))
+			if err != nil {
+				return "", err
+			}
+			return countDetail(rows, "rows")
+		}},
+	}
+
+	results := make([]HealthCheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runHealthCheck(ctx, check.name, check.run)
+		}()
+	}
+	wg.Wait()
+
+	report := HealthReport{
+		Status:    HealthStatusOK,
+		CheckedAt: time.Now().UTC(),
+		LeagueID:  LeagueID,
+		Year:      LeagueYear,
+		Checks:    append([]HealthCheckResult{secretResult}, results...),
+	}
+	for _, result := range report.Checks {
+		if result.Status != HealthStatusOK {
+			report.Status = HealthStatusFail
+		}
+	}
+
+	loggerFromContext(ctx).Info("health check complete", slog.String("status", report.Status))
+
+	return report
+}
+
+func runHealthCheck(ctx context.Context, name string,
+	run func(ctx context.Context) (string, error)) HealthCheckResult {
+	ctx, span := startSpan(ctx, "health."+name)
+	start := time.Now()
+	detail, err := run(ctx)
+	span.End(err)
+
+	result := HealthCheckResult{
+		Name:       name,
+		Status:     HealthStatusOK,
+		DurationMS: time.Since(start).Milliseconds(),
+		Detail:     detail,
+	}
+	switch {
+	case errors.Is(err, errNoAPIKey):
+		result.Status = HealthStatusSkipped
+		result.Error = redactError(err)
+	case err != nil:
+		result.Status = HealthStatusFail
+		result.Error = redactError(err)
+	}
+
+	return result
+}
+
+func countDetail(count int, noun string) (string, error) {
+	if count == 0 {
+		return "", fmt.Errorf("response contained no %s", noun)
+	}
+
+	return fmt.Sprintf("%d %s", count, noun), nil
+}
+
+// probeAllPlayTable fetches the power rankings page and confirms the scraper would still find the
+// AllPlay columns by header and team rows with numeric values in them.
+func probeAllPlayTable(ctx context.Context, client HTTPClient, pageURL string) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, http.NoBody)
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("power rankings page returned status %d", response.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(response.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	return inspectAllPlayTable(doc)
+}
+
+func inspectAllPlayTable(doc *goquery.Document) (int, error) {
+	tables := doc.Find("table.report")
+	if tables.Length() == 0 {
+		return 0, errors.New("no table.report found on power rankings page")
+	}
+
+	var stats []AllPlayTeamStats
+	var tableErr error
+	tables.EachWithBreak(func(_ int, table *goquery.Selection) bool {
+		stats, tableErr = parseAllPlayTable(newHTMLElement(table))
+		return tableErr != nil
+	})
+	if tableErr != nil {
+		return 0, tableErr
+	}
+
+	teams := filterTeams(stats)
+	if len(teams) == 0 {
+		return 0, errors.New("no team rows found in AllPlay table")
+	}
+
+	for _, team := range teams {
+		for _, value := range []string{team.AllPlayWins, team.AllPlayLosses, team.AllPlayTies, team.AllPlayPercentage} {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return 0, fmt.Errorf("AllPlay value for %q is not numeric: %q", team.FranchiseName, value)
+			}
+		}
+	}
+
+	return len(teams), nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+// allPlayRowHTML builds a power rankings row with the AllPlay values in columns 13-16.
+func allPlayRowHTML(name, wins, losses, ties, pct string) string {
+	cells := []string{name}
+	for i := 2; i <= 12; i++ {
+		cells = append(cells, fmt.Sprint(i))
+	}
+	cells = append(cells, wins, losses, ties, pct)
+
+	return "<tr><td>" + strings.Join(cells, "</td><td>") + "</td></tr>"
+}
+
+// allPlayPageHTML wraps rows in a power rankings table whose header labels the AllPlay columns.
+func allPlayPageHTML(rows ...string) string {
+	headers := []string{"Franchise"}
+	for i := 2; i <= 12; i++ {
+		headers = append(headers, fmt.Sprint("Col ", i))
+	}
+	headers = append(headers, "All-Play W", "All-Play L", "All-Play T", "All-Play Pct")
+
+	return `<html><body><table class="report"><thead><tr><th>` + strings.Join(headers, "</th><th>") +
+		`</th></tr></thead><tbody>` + strings.Join(rows, "") + `</tbody></table></body></html>`
+}
+
+func newMFLTestServer(t *testing.T, page string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("TYPE") {
+		case "league":
+			assert.NoError(t, json.NewEncoder(w).Encode(LeagueResponse{League: League{
+				Franchises: Franchises{Franchise: []Franchise{{TeamID: "0001"}, {TeamID: "0002"}}},
+			}}))
+		case "leagueStandings":
+			assert.NoError(t, json.NewEncoder(w).Encode(LeagueStandingsResponse{LeagueStandings: LeagueStandings{
+				Franchise: []Franchise{{TeamID: "0001"}, {TeamID: "0002"}},
+			}}))
+		default:
+			_, err := w.Write([]byte(page))
+			assert.NoError(t, err)
+		}
+	}))
+}
+
+func TestRunHealthChecks(t *testing.T) {
+	page := allPlayPageHTML(allPlayRowHTML(Team1Name, "10", "5", "0", ".667"),
+		allPlayRowHTML(Team2Name, "5", "10", "0", ".333"))
+	server := newMFLTestServer(t, page)
+	defer server.Close()
+
+	apiKey := func(context.Context) (string, error) { return "key", nil }
+	report := runHealthChecks(context.Background(),
+		healthDependencies{apiKey: apiKey, client: server.Client(), baseURL: server.URL + "/"})
+
+	assert.Equal(t, HealthStatusOK, report.Status)
+	assert.Len(t, report.Checks, 4)
+	for _, check := range report.Checks {
+		assert.Equal(t, HealthStatusOK, check.Status, check.Name)
+	}
+	assert.Equal(t, "2 rows", report.Checks[3].Detail)
+}
+
+// unreachableClient fails every request the way http.Client does when MFL can't be reached, with
+// the whole URL in the error.
+type unreachableClient struct{}
+
+func (unreachableClient) Do(req *http.Request) (*http.Response, error) {
+	return nil, &url.Error{Op: "Get", URL: req.URL.String(), Err: errors.New("dial tcp: connection refused")}
+}
+
+func TestRunHealthChecksRedactsAPIKey(t *testing.T) {
+	apiKey := func(context.Context) (string, error) { return "supersecret", nil }
+	report := runHealthChecks(context.Background(),
+		healthDependencies{apiKey: apiKey, client: unreachableClient{}, baseURL: "http://127.0.0.1:1/"})
+
+	body, err := json.Marshal(report)
+	assert.NoError(t, err)
+	assert.Equal(t, HealthStatusFail, report.Status)
+	assert.Contains(t, string(body), "connection refused")
+	assert.NotContains(t, string(body), "supersecret")
+}
+
+func TestRunHealthChecksSecretFailure(t *testing.T) {
+	server := newMFLTestServer(t, allPlayPageHTML(allPlayRowHTML(Team1Name, "10", "5", "0", ".667")))
+	defer server.Close()
+
+	apiKey := func(context.Context) (string, error) { return "", errors.New("access denied") }
+	report := runHealthChecks(context.Background(),
+		healthDependencies{apiKey: apiKey, client: server.Client(), baseURL: server.URL + "/"})
+
+	assert.Equal(t, HealthStatusFail, report.Status)
+	statuses := map[string]string{}
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	assert.Equal(t, map[string]string{
+		HealthCheckSecret:          HealthStatusFail,
+		HealthCheckLeague:          HealthStatusSkipped,
+		HealthCheckLeagueStandings: HealthStatusSkipped,
+		HealthCheckAllPlayTable:    HealthStatusOK,
+	}, statuses)
+}
+
+func TestInspectAllPlayTable(t *testing.T) {
+	testCases := []struct {
+		name        string
+		page        string
+		expected    int
+		expectError bool
+	}{
+		{name: "team row", page: allPlayPageHTML(allPlayRowHTML(Team1Name, "1", "2", "0", ".333")), expected: 1},
+		{name: "no rows", page: allPlayPageHTML(), expectError: true},
+		{name: "non-numeric column", page: allPlayPageHTML(allPlayRowHTML(Team1Name, "1", "2", "0", "n/a")), expectError: true},
+		{name: "only non-team rows", page: allPlayPageHTML(allPlayRowHTML("2nd", "1", "2", "0", ".333")), expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tc.page))
+			if err != nil {
+				t.Fatalf("Failed to parse fixture: %v", err)
+			}
+			rows, err := inspectAllPlayTable(doc)
+			if (err != nil) != tc.expectError {
+				t.Fatalf("inspectAllPlayTable() error = %v, expectError %v", err, tc.expectError)
+			}
+			assert.Equal(t, tc.expected, rows)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+type loggerContextKey struct{}
+
+// newLogger returns a JSON logger so CloudWatch Logs Insights can query individual fields.
+// LOG_LEVEL accepts debug, info, warn or error and defaults to info.
+func newLogger(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: logLevel(os.Getenv("LOG_LEVEL"))}))
+}
+
+func logLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// requestLogger annotates the base logger with the fields every log line for a request should carry.
+func requestLogger(ctx context.Context, base *slog.Logger, request events.APIGatewayProxyRequest) *slog.Logger {
+	attrs := []any{
+		slog.String("request_id", request.RequestContext.RequestID),
+		slog.String("league_id", LeagueID),
+		slog.String("league_year", LeagueYear),
+		slog.String("stage", request.RequestContext.Stage),
+	}
+
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		attrs = append(attrs, slog.String("aws_request_id", lc.AwsRequestID))
+	}
+
+	return base.With(attrs...)
+}
+
+func withLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// loggerFromContext returns the request logger, falling back to the default logger so callers
+// never need a nil check.
+func loggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
+}
+
+// logUpstream records how long a call to MFL took and whether it succeeded.
+func logUpstream(ctx context.Context, upstream string, start time.Time, err error) {
+	logger := loggerFromContext(ctx)
+	duration := slog.Int64("duration_ms", time.Since(start).Milliseconds())
+
+	if err != nil {
+		logger.Error("upstream request failed", slog.String("upstream", upstream), duration,
+			slog.String("outcome", "error"), slog.String("error", redactError(err)))
+		return
+	}
+
+	logger.Info("upstream request complete", slog.String("upstream", upstream), duration,
+		slog.String("outcome", "success"))
+}
+
+// redactAPIKey strips the trailing APIKEY parameter from a URL before it is logged.
+func redactAPIKey(url, apiKey string) string {
+	if apiKey == "" {
+		return url
+	}
+
+	return strings.TrimSuffix(url, apiKey)
+}
+
+var apiKeyParameter = regexp.MustCompile(`APIKEY=[^&\s"]*`)
+
+// redactError is the error's text with any APIKEY parameter emptied. A failed request to MFL is a
+// *url.Error, and its text carries the whole export URL, key included.
+func redactError(err error) string {
+	return apiKeyParameter.ReplaceAllString(err.Error(), "APIKEY=")
+}
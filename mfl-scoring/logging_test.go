@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var line map[string]any
+		if err := decoder.Decode(&line); err != nil {
+			t.Fatalf("log output is not JSON: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestLogLevel(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected slog.Level
+	}{
+		{input: "debug", expected: slog.LevelDebug},
+		{input: "WARN", expected: slog.LevelWarn},
+		{input: "error", expected: slog.LevelError},
+		{input: "", expected: slog.LevelInfo},
+		{input: "verbose", expected: slog.LevelInfo},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.expected, logLevel(tc.input))
+		})
+	}
+}
+
+func TestRequestLoggerCarriesCorrelationFields(t *testing.T) {
+	var buf bytes.Buffer
+	request := events.APIGatewayProxyRequest{
+		RequestContext: events.APIGatewayProxyRequestContext{RequestID: "abc-123", Stage: "prod"},
+	}
+
+	logger := requestLogger(context.Background(), newLogger(&buf), request)
+	logger.Info("hello")
+
+	lines := decodeLogLines(t, &buf)
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 log line, got %d", len(lines))
+	}
+	assert.Equal(t, "abc-123", lines[0]["request_id"])
+	assert.Equal(t, "prod", lines[0]["stage"])
+	assert.Equal(t, LeagueID, lines[0]["league_id"])
+	assert.Equal(t, LeagueYear, lines[0]["league_year"])
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	assert.Equal(t, slog.Default(), loggerFromContext(context.Background()))
+
+	logger := newLogger(&bytes.Buffer{})
+	assert.Equal(t, logger, loggerFromContext(withLogger(context.Background(), logger)))
+}
+
+func TestLogUpstream(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := withLogger(context.Background(), newLogger(&buf))
+
+	logUpstream(ctx, "league", time.Now(), nil)
+	logUpstream(ctx, "leagueStandings", time.Now(), errors.New("timeout"))
+
+	lines := decodeLogLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d", len(lines))
+	}
+	assert.Equal(t, "league", lines[0]["upstream"])
+	assert.Equal(t, "success", lines[0]["outcome"])
+	assert.Contains(t, lines[0], "duration_ms")
+	assert.Equal(t, "leagueStandings", lines[1]["upstream"])
+	assert.Equal(t, "error", lines[1]["outcome"])
+	assert.Equal(t, "timeout", lines[1]["error"])
+}
+
+func TestLogUpstreamRedactsAPIKey(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := withLogger(context.Background(), newLogger(&buf))
+
+	logUpstream(ctx, "league", time.Now(), &url.Error{Op: "Get",
+		URL: "https://www46.myfantasyleague.com/2025/export?TYPE=league&L=1&APIKEY=supersecret",
+		Err: errors.New("dial tcp: connection refused")})
+
+	assert.NotContains(t, buf.String(), "supersecret")
+	lines := decodeLogLines(t, &buf)
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 log line, got %d", len(lines))
+	}
+	assert.Equal(t, `Get "https://www46.myfantasyleague.com/2025/export?TYPE=league&L=1&APIKEY=": `+
+		"dial tcp: connection refused", lines[0]["error"])
+}
+
+func TestRedactAPIKey(t *testing.T) {
+	assert.Equal(t, "https://example.com/export?L=1&APIKEY=",
+		redactAPIKey("https://example.com/export?L=1&APIKEY=secret", "secret"))
+	assert.Equal(t, "https://example.com/export?L=1", redactAPIKey("https://example.com/export?L=1", ""))
+}
@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"math"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"regexp"
 	"sort"
@@ -18,6 +20,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-secretsmanager-caching-go/secretcache"
@@ -33,12 +36,14 @@ type LeagueResponse struct {
 }
 
 type League struct {
-	Franchises Franchises `json:"franchises"`
-	ID         string     `json:"id"`
-	History    History    `json:"history"`
-	Name       string     `json:"name"`
-	H2H        string     `json:"h2h"`
-	BaseURL    string     `json:"baseURL"`
+	Franchises  Franchises  `json:"franchises"`
+	Divisions   Divisions   `json:"divisions"`
+	Conferences Conferences `json:"conferences"`
+	ID          string      `json:"id"`
+	History     History     `json:"history"`
+	Name        string      `json:"name"`
+	H2H         string      `json:"h2h"`
+	BaseURL     string      `json:"baseURL"`
 }
 
 type Franchises struct {
@@ -66,6 +71,9 @@ type Franchise struct {
 	TeamID                  string `json:"id"`
 	TeamName                string `json:"name"`
 	OwnerName               string `json:"owner_name"`
+	OwnerID                 string
+	OwnerAlias              string
+	Division                string `json:"division"`
 	RecordWins              int
 	RecordWinsString        string `json:"h2hw"`
 	RecordLosses            int
@@ -73,13 +81,25 @@ type Franchise struct {
 	RecordTies              int
 	RecordTiesString        string `json:"h2ht"`
 	Record                  string
+	MedianWins              int
+	MedianLosses            int
+	MedianTies              int
+	MedianRecord            string
 	PointsFor               float64
 	PointsForString         string `json:"pf"`
+	PointsAgainst           float64
+	PointsAgainstString     string `json:"pa"`
+	PotentialPoints         float64
+	PotentialPointsString   string `json:"pp"`
+	LineupEfficiency        float64
 	PointScore              float64
 	PointScoreString        string
 	RecordMagic             float64
 	RecordScore             float64
 	RecordScoreString       string
+	PointsAgainstScore      float64
+	PotentialPointsScore    float64
+	EfficiencyScore         float64
 	TotalScoreString        string
 	TotalScore              float64
 	AllPlayWins             int
@@ -88,6 +108,9 @@ type Franchise struct {
 	AllPlayRecord           string
 	AllPlayPercentageString string
 	AllPlayPercentage       float64
+	DivisionWinner          bool
+	DivisionBonus           float64
+	DisplayName             string
 }
 
 const (
@@ -95,15 +118,18 @@ const (
 	LeagueYear              string = "2025"
 	LeagueAPIQuery          string = "TYPE=league"
 	LeagueStandingsAPIQuery string = "TYPE=leagueStandings"
+	ScheduleAPIQuery        string = "TYPE=schedule"
 	LeagueAPIPath           string = "export?"
 	LeagueWebPath           string = "options?"
 	PowerRankingsTableQuery string = "O=101"
 	LeagueOutputSortQuery   string = "SORT=ALLPLAY"
-	LeagueIDQuery           string = "L=15781"
+	LeagueID                string = "15781"
+	LeagueIDQuery           string = "L=" + LeagueID
 	APIOutputTypeQuery      string = "JSON=1"
 )
 
 type AllPlayTeamStats struct {
+	FranchiseID       string
 	FranchiseName     string
 	AllPlayWins       string
 	AllPlayLosses     string
@@ -112,21 +138,149 @@ type AllPlayTeamStats struct {
 }
 
 func main() {
-	lambda.Start(handler)
+	slog.SetDefault(newLogger(os.Stdout))
+	lambda.Start(invoke)
 }
 
-func handler(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	secretCache, err := secretcache.New()
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	logger := requestLogger(ctx, slog.Default(), request)
+	ctx = withLogger(ctx, logger)
+	telemetry := newTelemetry(request.RequestContext.Stage, newSpanExporter())
+	ctx = withTelemetry(ctx, telemetry)
+	defer telemetry.Flush(ctx, os.Stdout, os.Getenv(lambdaFunctionNameEnv) != "")
+
+	ctx, span := startSpan(ctx, "handler", slog.String("stage", request.RequestContext.Stage))
+	start := time.Now()
+
+	logger.Info("request received", slog.String("domain", request.RequestContext.DomainName),
+		slog.Any("query", request.QueryStringParameters))
+
+	response, err := respond(ctx, request)
+	span.End(err)
+
+	logger.Info("request complete", slog.Int("status", response.StatusCode),
+		slog.Int64("duration_ms", time.Since(start).Milliseconds()))
+
+	return response, err
+}
+
+func respond(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if handle, params := matchRoute(routes(), request.HTTPMethod, request.Path); handle != nil {
+		return handle(ctx, request, params)
+	}
+
+	if request.QueryStringParameters["explain"] == "1" {
+		return serveExplanations(ctx, request)
+	}
+
+	format, err := negotiateFormat(request.QueryStringParameters["output"], headerValue(request.Headers, "Accept"))
 	if err != nil {
-		return events.APIGatewayProxyResponse{}, err
+		return textResponse(http.StatusNotAcceptable, err.Error()), nil
 	}
 
-	var APIKeySecretID = os.Getenv("API_KEY_SECRET_ID")
-	apiKey, err := secretCache.GetSecretString(APIKeySecretID)
+	standings, policy, err := standingsForRoute(ctx, "/", request)
 	if err != nil {
 		return events.APIGatewayProxyResponse{}, err
 	}
 
+	return renderStandings(format, standings, policy, time.Now())
+}
+
+// standingsForRoute scores the league and resolves the display policy for the route serving it.
+func standingsForRoute(ctx context.Context, routeName string,
+	request events.APIGatewayProxyRequest) (Standings, DisplayPolicy, error) {
+	standings, err := computeStandings(ctx)
+	if err != nil {
+		return Standings{}, DisplayPolicy{}, err
+	}
+
+	config, err := currentConfig()
+	if err != nil {
+		return Standings{}, DisplayPolicy{}, err
+	}
+
+	return standings, config.Display.policyFor(routeName, request), nil
+}
+
+func formatWarnings(warnings []string) string {
+	var b strings.Builder
+	for _, warning := range warnings {
+		b.WriteString("\n\nWarning: " + warning)
+	}
+
+	return b.String()
+}
+
+var (
+	secretCache     *secretcache.Cache
+	secretCacheMu   sync.Mutex
+	secretCacheWarm bool
+)
+
+// getAPIKey reads the MFL API key through a cache that lives as long as the Lambda container.
+func getAPIKey(ctx context.Context) (string, error) {
+	ctx, span := startSpan(ctx, "secret.apiKey")
+	start := time.Now()
+
+	secretCacheMu.Lock()
+	defer secretCacheMu.Unlock()
+
+	if secretCache == nil {
+		cache, err := secretcache.New()
+		if err != nil {
+			span.End(err)
+			return "", err
+		}
+		secretCache = cache
+	}
+
+	if secretCacheWarm {
+		telemetryFromContext(ctx).Count(MetricSecretCacheHits, 1)
+		span.SetAttribute("cache_hit", true)
+	}
+
+	apiKey, err := secretCache.GetSecretString(os.Getenv("API_KEY_SECRET_ID"))
+	logUpstream(ctx, "secretsmanager", start, err)
+	span.End(err)
+	if err != nil {
+		telemetryFromContext(ctx).Count(MetricUpstreamErrors, 1)
+		return "", err
+	}
+	secretCacheWarm = true
+
+	return apiKey, nil
+}
+
+// Standings is the scored league plus anything the reader should know about how it was produced.
+type Standings struct {
+	Franchises  Franchises
+	Warnings    []string
+	LeagueName  string
+	FetchedAt   time.Time
+	Divisions   []Division
+	Conferences []Conference
+	// Scoring is the config the standings were scored with, so anything that rescores them
+	// (simulations, what-ifs, recaps) follows the same rules.
+	Scoring ScoringConfig
+	// History lists the league's earlier seasons.
+	History History
+}
+
+// computeStandings fetches everything from MFL and runs the full championship scoring pipeline.
+func computeStandings(ctx context.Context) (Standings, error) {
+	logger := loggerFromContext(ctx)
+	telemetry := telemetryFromContext(ctx)
+
+	apiKey, err := getAPIKey(ctx)
+	if err != nil {
+		return Standings{}, err
+	}
+
+	config, err := currentConfig()
+	if err != nil {
+		return Standings{}, err
+	}
+
 	var wg sync.WaitGroup
 	wg.Add(2)
 
@@ -136,114 +290,185 @@ func handler(request events.APIGatewayProxyRequest) (events.APIGatewayProxyRespo
 	errChan := make(chan error, 2)
 
 	go func() {
-		LeagueAPIURL := MflURL + LeagueYear + "/" + LeagueAPIPath + LeagueAPIQuery + "&" +
-			LeagueIDQuery + "&" + APIOutputTypeQuery + "&APIKEY=" + apiKey
-		// parts := strings.Split(LeagueAPIURL, "&APIKEY=")
-		lengthWithoutAPIKey := len(LeagueAPIURL) - len(apiKey)
-		cleanedURL := LeagueAPIURL[0:lengthWithoutAPIKey]
-		log.Println(cleanedURL)
+		defer wg.Done()
+		LeagueAPIURL := leagueAPIURL(MflURL, apiKey)
+		logger.Debug("fetching league", slog.String("url", redactAPIKey(LeagueAPIURL, apiKey)))
+		_, span := startSpan(ctx, "fetch.league")
 		client := &http.Client{}
+		fetchStart := time.Now()
+		var err error
 		franchiseDetails, err = getFranchiseDetails(client, LeagueAPIURL)
+		logUpstream(ctx, "league", fetchStart, err)
+		span.End(err)
 		if err != nil {
+			telemetry.Count(MetricUpstreamErrors, 1)
 			errChan <- err
-			return
 		}
-		wg.Done()
 	}()
 
 	go func() {
-		LeagueStandingsAPIURL := MflURL + LeagueYear + "/" + LeagueAPIPath + LeagueStandingsAPIQuery + "&" +
-			LeagueIDQuery + "&" + APIOutputTypeQuery + "&APIKEY=" + apiKey
-		lengthWithoutAPIKey := len(LeagueStandingsAPIURL) - len(apiKey)
-		cleanedURL := LeagueStandingsAPIURL[0:lengthWithoutAPIKey]
-		log.Println(cleanedURL)
-
+		defer wg.Done()
+		LeagueStandingsAPIURL := leagueStandingsAPIURL(MflURL, apiKey)
+		logger.Debug("fetching league standings", slog.String("url", redactAPIKey(LeagueStandingsAPIURL, apiKey)))
+		_, span := startSpan(ctx, "fetch.leagueStandings")
 		client := &http.Client{}
+		fetchStart := time.Now()
+		var err error
 		leagueStandings, err = getLeagueStandings(client, LeagueStandingsAPIURL)
+		logUpstream(ctx, "leagueStandings", fetchStart, err)
+		span.End(err)
 		if err != nil {
+			telemetry.Count(MetricUpstreamErrors, 1)
 			errChan <- err
-			return
 		}
-		wg.Done()
 	}()
 
 	wg.Wait()
 	close(errChan)
+	fetchedAt := time.Now()
 
 	for err := range errChan {
 		if err != nil {
-			return events.APIGatewayProxyResponse{}, err
+			return Standings{}, err
 		}
 	}
 
+	_, scoringSpan := startSpan(ctx, "scoring")
+	if unmatched := unmatchedStandings(franchiseDetails, leagueStandings); len(unmatched) > 0 {
+		telemetry.Count(MetricUnmatchedFranchises, float64(len(unmatched)))
+		logger.Warn("franchises missing from standings", slog.Any("team_ids", unmatched))
+	}
+
 	// Populate the slice of Franchise objects with league standing data
 	franchisesWithStandings, err := associateStandingsWithFranchises(franchiseDetails, leagueStandings)
 	if err != nil {
-		return events.APIGatewayProxyResponse{}, err
+		scoringSpan.End(err)
+		logger.Error("associating standings failed", slog.String("error", err.Error()))
+		return Standings{}, err
+	}
+	currentYear, _ := convertStringToInteger(LeagueYear) //nolint:errcheck // LeagueYear is a constant.
+	franchisesWithStandings = applyOwnerRegistry(franchisesWithStandings, config.Owners, currentYear)
+	scoring := config.Scoring
+	scoring.Format = scoring.resolveFormat(detectLeagueFormat(franchiseDetails.League, leagueStandings.LeagueStandings))
+	if scoring.Format == LeagueHeadToHead {
+		franchisesWithStandings = populateHeadToHeadRecords(franchisesWithStandings)
+	}
+	var warnings []string
+	if scoring.Format == LeaguePointsOnly && scoring.scoresMedian() {
+		warnings = append(warnings, "Median scoring is off: points-only leagues have no schedule to "+
+			"take weekly scores from.")
+		scoring.Median = MedianOff
+	}
+	if scoring.scoresMedian() {
+		// The median record isn't in the standings export, so it is rebuilt from the weekly scores.
+		games, err := fetchSchedule(ctx)
+		if err != nil {
+			scoringSpan.End(err)
+			return Standings{}, err
+		}
+		franchisesWithStandings = applyMedianRecords(franchisesWithStandings, games)
+	}
+
+	calculatedTotalScore := scoreChampionship(franchisesWithStandings, scoring)
+	scoringSpan.End(nil)
+
+	scrapeCtx, scrapeSpan := startSpan(ctx, "scrape.allPlay")
+	scrapeCtx, cancel := context.WithTimeout(scrapeCtx, scrapeTimeout)
+	scrapeStart := time.Now()
+	allPlayTeamData, scrapeErr := scrape(scrapeCtx, allPlayPageURL(MflURL))
+	cancel()
+	logUpstream(ctx, "allPlayScrape", scrapeStart, scrapeErr)
+	scrapeSpan.SetAttribute("rows", len(allPlayTeamData))
+	scrapeSpan.End(scrapeErr)
+
+	if scrapeErr != nil {
+		telemetry.Count(MetricUpstreamErrors, 1)
+		if !shouldDegradeWithoutAllPlay(ctx, scrapeErr) {
+			return Standings{}, scrapeErr
+		}
+		warnings = append(warnings, "AllPlay data unavailable, so AllPlay columns are empty and the "+
+			"AllPlay tiebreaker was skipped: "+scrapeErr.Error())
+		logger.Warn("continuing without AllPlay data", slog.String("error", scrapeErr.Error()))
 	}
-	populatedHeadToHeadRecords := populateHeadToHeadRecords(franchisesWithStandings)
 
+	_, allPlaySpan := startSpan(ctx, "scoring.allPlay")
+	if unmatched := unmatchedAllPlay(calculatedTotalScore, allPlayTeamData); scrapeErr == nil && len(unmatched) > 0 {
+		telemetry.Count(MetricUnmatchedFranchises, float64(len(unmatched)))
+		logger.Warn("franchises missing from AllPlay table", slog.Any("team_ids", unmatched))
+	}
+
+	franchisesWithStandingsAndAllplay, err := appendAllPlay(calculatedTotalScore, allPlayTeamData)
+	if err != nil {
+		allPlaySpan.End(err)
+		logger.Error("appending AllPlay data failed", slog.String("error", err.Error()))
+		return Standings{}, err
+	}
+
+	populatedAllPlayRecords := populateAllPlayRecords(franchisesWithStandingsAndAllplay)
+
+	sortedFranchises := rankFranchises(populatedAllPlayRecords, scoring)
+	allPlaySpan.End(nil)
+
+	logger.Info("scoring complete", slog.Int("franchises", len(sortedFranchises.Franchise)),
+		slog.Int("warnings", len(warnings)))
+
+	return Standings{
+		Franchises:  sortedFranchises,
+		Warnings:    warnings,
+		LeagueName:  franchiseDetails.League.Name,
+		FetchedAt:   fetchedAt,
+		Divisions:   franchiseDetails.League.Divisions.Division,
+		Conferences: franchiseDetails.League.Conferences.Conference,
+		Scoring:     scoring,
+		History:     franchiseDetails.League.History,
+	}, nil
+}
+
+// scoreChampionship awards championship points for fantasy points and record. It reorders and
+// updates the franchises in place; rankFranchises then applies the tiebreakers. Points-only leagues
+// have no record to score, so only fantasy points count.
+func scoreChampionship(franchises Franchises, scoring ScoringConfig) Franchises {
 	// Put teams in order of most fantasy points scored
-	sort.Sort(ByPointsFor{populatedHeadToHeadRecords})
-	// fmt.Printf("%+v \n", populatedHeadToHeadRecords)
+	sort.Sort(ByPointsFor{franchises})
 
 	// Assign points to teams based on fantasy points scored, sharing points as necessary when teams tie
-	calculatedPointScore := calculatePointsScore(populatedHeadToHeadRecords)
+	calculatedPointScore := calculatePointsScore(franchises)
+	calculatedPointScore = calculateComponentScores(calculatedPointScore, scoring.Components)
+
+	if scoring.Format == LeaguePointsOnly {
+		for i := range calculatedPointScore.Franchise {
+			calculatedPointScore.Franchise[i].RecordMagic = 0
+			calculatedPointScore.Franchise[i].RecordScore = 0
+			calculatedPointScore.Franchise[i].RecordScoreString = ""
+		}
+		return calculateTotalScore(calculatedPointScore)
+	}
 
-	// Put teams in order of best head to head record
-	calculatedRecordMagic := calculateRecordMagic(calculatedPointScore)
+	// Put teams in order of best record
+	calculatedRecordMagic := calculateRecordMagic(calculatedPointScore, scoring)
 	sort.Sort(ByRecordMagic{calculatedRecordMagic})
 
-	// Assign points to teams based on head to head record, sharing points as necessary when teams tie
+	// Assign points to teams based on record, sharing points as necessary when teams tie
 	calculatedRecordScore := calculateRecordScore(calculatedRecordMagic)
 
 	// totalScore = points assigned for fantasy points + points assigned for record
-	calculatedTotalScore := calculateTotalScore(calculatedRecordScore)
-
-	allPlayTeamData := scrape()
-	franchisesWithStandingsAndAllplay, err := appendAllPlay(calculatedTotalScore, allPlayTeamData)
-	if err != nil {
-		return events.APIGatewayProxyResponse{}, err
-	}
+	return calculateTotalScore(calculatedRecordScore)
+}
 
-	populatedAllPlayRecords := populateAllPlayRecords(franchisesWithStandingsAndAllplay)
-	// fmt.Println("populatedAllPlayRecords: ", populatedAllPlayRecords)
-
-	sortedFranchises := sortFranchises(populatedAllPlayRecords)
-
-	// fmt.Printf("requestContext.DomainName: %v\n", request.RequestContext.DomainName)
-	// fmt.Printf("requestContext.QueryStringParameters: %v\n", request.QueryStringParameters)
-	if outputFormat, exists := request.QueryStringParameters["output"]; exists {
-		if outputFormat == "json" {
-			headers := map[string]string{
-				"content-type":                     "application/json",
-				"Access-Control-Allow-Origin":      "*",
-				"Access-Control-Allow-Credentials": "true",
-			}
-			fmt.Println("headers: ", headers)
-			body, err := json.Marshal(sortedFranchises)
-			if err != nil {
-				panic(err)
-			}
-			return events.APIGatewayProxyResponse{
-				Headers:    headers,
-				Body:       string(body),
-				StatusCode: 200,
-			}, nil
-		}
+// shouldDegradeWithoutAllPlay decides whether a failed scrape should still produce a table. A
+// request whose own deadline has passed always fails, and ALLPLAY_FAILURE_MODE=fail turns every
+// scrape error into a failed request.
+func shouldDegradeWithoutAllPlay(ctx context.Context, scrapeErr error) bool {
+	if ctx.Err() != nil {
+		return false
 	}
 
-	if strings.Contains(request.RequestContext.DomainName, "execute-api") {
-		return events.APIGatewayProxyResponse{
-			Body:       printScoringTableCouthly(sortedFranchises),
-			StatusCode: 200,
-		}, nil
+	if strings.EqualFold(os.Getenv("ALLPLAY_FAILURE_MODE"), "fail") {
+		return false
 	}
 
-	return events.APIGatewayProxyResponse{
-		Body:       printScoringTableUncouthly(sortedFranchises),
-		StatusCode: 200,
-	}, nil
+	return errors.Is(scrapeErr, ErrScrapeRequest) || errors.Is(scrapeErr, ErrNoAllPlayRows) ||
+		errors.Is(scrapeErr, ErrAllPlaySchema) || errors.Is(scrapeErr, ErrScrapeCanceled)
 }
 
 // type Franchises struct { []Franchise }
@@ -296,74 +521,171 @@ func sortFranchises(teams Franchises) Franchises {
 	return teams
 }
 
+// rankFranchises puts scored franchises in championship order and awards the division titles. A
+// division bonus can change the order, so it is sorted again.
+func rankFranchises(franchises Franchises, scoring ScoringConfig) Franchises {
+	ranked := sortFranchises(franchises)
+	awardDivisionTitles(ranked, scoring)
+	if scoring.DivisionWinnerBonus != 0 {
+		ranked = sortFranchises(ranked)
+	}
+
+	return ranked
+}
+
 const (
 	TotalPts      string = "Total Pts"
 	Record        string = "W-L-T"
+	MedianRecord  string = "Median W-L-T"
 	FantasyPts    string = "Fantasy Pts"
 	PtsScore      string = "Pts Score"
 	RecScore      string = "Rcrd Score"
 	AllPlayRecord string = "AllPlay W-L-T"
 	AllPlayPct    string = "AllPlay %"
+	PtsAgainst    string = "Pts Against"
+	PotentialPts  string = "Potential Pts"
+	Efficiency    string = "Efficiency"
+	PAScore       string = "PA Score"
+	PPScore       string = "PP Score"
+	EffScore      string = "Eff Score"
+	Luck          string = "Luck"
 )
 
-func printScoringTableUncouthly(teams Franchises) string {
-	t := table.NewWriter()
-	t.SetOutputMirror(&bytes.Buffer{})
-	t.AppendHeader(table.Row{"Team Name", "Owner", Record, FantasyPts, PtsScore, RecScore, TotalPts,
-		AllPlayRecord, AllPlayPct})
-	for _, o := range teams.Franchise {
-		t.AppendRow([]interface{}{o.TeamName, o.OwnerName, o.Record, o.PointsForString, o.PointScore,
-			o.RecordScoreString, o.TotalScoreString, o.AllPlayRecord, o.AllPlayPercentageString})
+// tableColumn is one column of the standings table and how to fill it from a franchise.
+type tableColumn struct {
+	header string
+	value  func(Franchise) any
+}
+
+// standingsColumns lists the table's columns after the name columns. Points against, potential
+// points and the optional components only appear when the league reports or scores them.
+func standingsColumns(teams Franchises) []tableColumn {
+	var reportsPointsAgainst, reportsPotentialPoints bool
+	var scoresPointsAgainst, scoresPotentialPoints, scoresEfficiency, hasLuck bool
+	for _, f := range teams.Franchise {
+		reportsPointsAgainst = reportsPointsAgainst || f.PointsAgainstString != ""
+		reportsPotentialPoints = reportsPotentialPoints || f.PotentialPointsString != ""
+		scoresPointsAgainst = scoresPointsAgainst || f.PointsAgainstScore != 0
+		scoresPotentialPoints = scoresPotentialPoints || f.PotentialPointsScore != 0
+		scoresEfficiency = scoresEfficiency || f.EfficiencyScore != 0
+		_, ok := luckIndex(f)
+		hasLuck = hasLuck || ok
 	}
 
-	columnConfigs := []table.ColumnConfig{
-		{Name: Record, Align: text.AlignCenter},
-		{Name: FantasyPts, Align: text.AlignCenter},
-		{Name: PtsScore, Align: text.AlignCenter},
-		{Name: RecScore, Align: text.AlignCenter},
-		{Name: TotalPts, Align: text.AlignCenter},
-		{Name: AllPlayRecord, Align: text.AlignCenter},
-		{Name: AllPlayPct, Align: text.AlignCenter},
+	columns := []tableColumn{{Record, func(f Franchise) any { return f.Record }}}
+	if hasMedianRecords(teams) {
+		columns = append(columns, tableColumn{MedianRecord, func(f Franchise) any { return f.MedianRecord }})
+	}
+	columns = append(columns, tableColumn{FantasyPts, func(f Franchise) any { return f.PointsForString }})
+	if reportsPointsAgainst {
+		columns = append(columns, tableColumn{PtsAgainst, func(f Franchise) any { return f.PointsAgainstString }})
+	}
+	if reportsPotentialPoints {
+		columns = append(columns,
+			tableColumn{PotentialPts, func(f Franchise) any { return f.PotentialPointsString }},
+			tableColumn{Efficiency, func(f Franchise) any { return formatEfficiency(f.LineupEfficiency) }})
+	}
+	columns = append(columns,
+		tableColumn{PtsScore, func(f Franchise) any { return f.PointScore }},
+		tableColumn{RecScore, func(f Franchise) any { return f.RecordScoreString }})
+	if scoresPointsAgainst {
+		columns = append(columns, tableColumn{PAScore, func(f Franchise) any { return f.PointsAgainstScore }})
+	}
+	if scoresPotentialPoints {
+		columns = append(columns, tableColumn{PPScore, func(f Franchise) any { return f.PotentialPointsScore }})
+	}
+	if scoresEfficiency {
+		columns = append(columns, tableColumn{EffScore, func(f Franchise) any { return f.EfficiencyScore }})
 	}
 
-	t.SetColumnConfigs(columnConfigs)
-	// t.SortBy(sortBy)
-	return t.Render()
+	columns = append(columns,
+		tableColumn{TotalPts, func(f Franchise) any { return f.TotalScoreString }},
+		tableColumn{AllPlayRecord, func(f Franchise) any { return f.AllPlayRecord }},
+		tableColumn{AllPlayPct, func(f Franchise) any { return f.AllPlayPercentageString }})
+	if hasLuck {
+		columns = append(columns, tableColumn{Luck, func(f Franchise) any {
+			if luck, ok := luckIndex(f); ok {
+				return formatLuck(luck)
+			}
+			return ""
+		}})
+	}
+
+	return columns
+}
+
+func printScoringTableUncouthly(teams Franchises) string {
+	return scoringTableWriter(teams, "").Render()
+}
+
+// printScoringTable renders the text table with only the names the display policy allows.
+func printScoringTable(teams Franchises, policy DisplayPolicy) string {
+	if !policy.HidesTeamNames() {
+		return printScoringTableUncouthly(applyDisplayPolicy(teams, policy))
+	}
+
+	return printScoringTableWithLabel(applyDisplayPolicy(teams, policy), displayLabelHeader(policy.Mode))
 }
 
 // Hide uncouth team names for professional project.
 func printScoringTableCouthly(teams Franchises) string {
+	return printScoringTableWithLabel(teams, displayLabelHeader(DisplayTeamID))
+}
+
+func printScoringTableWithLabel(teams Franchises, labelHeader string) string {
+	return scoringTableWriter(teams, labelHeader).Render() +
+		"\n\nTeam names are hidden. There are some weirdos in this league. "
+}
+
+// scoringTableWriter builds the standings table shared by every tabular output format. An empty
+// labelHeader shows the team name and owner columns; otherwise a single DisplayName column is shown
+// under that header. Optional columns are only shown when the league has data for them.
+func scoringTableWriter(teams Franchises, labelHeader string) table.Writer {
 	t := table.NewWriter()
 	t.SetOutputMirror(&bytes.Buffer{})
-	t.AppendHeader(table.Row{"Team ID", Record, FantasyPts, PtsScore, RecScore, TotalPts,
-		AllPlayRecord, AllPlayPct})
-	for _, o := range teams.Franchise {
-		t.AppendRow([]interface{}{o.TeamID, o.Record, o.PointsForString, o.PointScore,
-			o.RecordScoreString, o.TotalScoreString, o.AllPlayRecord, o.AllPlayPercentageString})
+	columns := standingsColumns(teams)
+
+	header := table.Row{labelHeader}
+	if labelHeader == "" {
+		header = table.Row{"Team Name", "Owner"}
 	}
+	columnConfigs := make([]table.ColumnConfig, 0, len(columns))
+	for _, column := range columns {
+		header = append(header, column.header)
+		columnConfigs = append(columnConfigs, table.ColumnConfig{Name: column.header, Align: text.AlignCenter})
+	}
+	t.AppendHeader(header)
 
-	columnConfigs := []table.ColumnConfig{
-		{Name: Record, Align: text.AlignCenter},
-		{Name: FantasyPts, Align: text.AlignCenter},
-		{Name: PtsScore, Align: text.AlignCenter},
-		{Name: RecScore, Align: text.AlignCenter},
-		{Name: TotalPts, Align: text.AlignCenter},
-		{Name: AllPlayRecord, Align: text.AlignCenter},
-		{Name: AllPlayPct, Align: text.AlignCenter},
+	for _, o := range teams.Franchise {
+		row := table.Row{displayLabel(o)}
+		if labelHeader == "" {
+			row = table.Row{o.TeamName, o.OwnerName}
+		}
+		for _, column := range columns {
+			row = append(row, column.value(o))
+		}
+		t.AppendRow(row)
 	}
 
 	t.SetColumnConfigs(columnConfigs)
-	// t.SortBy(sortBy)
-	return t.Render() +
-		"\n\nTeam names are hidden. There are some weirdos in this league. "
+	return t
+}
+
+// displayLabel is the name a franchise is shown under once the display policy has been applied.
+func displayLabel(franchise Franchise) string {
+	if franchise.DisplayName != "" {
+		return franchise.DisplayName
+	}
+
+	return franchise.TeamID
 }
 
 func calculateTotalScore(franchises Franchises) Franchises {
 	for i := range franchises.Franchise {
 		// for i := 0; i < len(franchises); i++ {
-		franchises.Franchise[i].TotalScore = franchises.Franchise[i].PointScore + franchises.Franchise[i].RecordScore
-		franchises.Franchise[i].TotalScoreString =
-			strconv.FormatFloat(franchises.Franchise[i].PointScore+franchises.Franchise[i].RecordScore, 'f', 1, 64)
+		f := &franchises.Franchise[i]
+		f.TotalScore = f.PointScore + f.RecordScore + f.PointsAgainstScore + f.PotentialPointsScore + f.EfficiencyScore
+		f.TotalScoreString = strconv.FormatFloat(f.TotalScore, 'f', 1, 64)
 	}
 
 	return franchises
@@ -399,15 +721,54 @@ func calculatePointsScore(franchises Franchises) Franchises {
 	return franchises
 }
 
-func calculateRecordMagic(franchises Franchises) Franchises {
+// calculateRecordMagic ranks records by the league's record metric. The median mode decides whether
+// the head to head record, the record against the weekly median or both are counted.
+func calculateRecordMagic(franchises Franchises, scoring ScoringConfig) Franchises {
+	leaderMargin := math.Inf(-1)
+	for _, f := range franchises.Franchise {
+		wins, losses, _ := scoredRecord(f, scoring.Median)
+		leaderMargin = math.Max(leaderMargin, float64(wins-losses))
+	}
+
 	for i := range franchises.Franchise {
-		franchises.Franchise[i].RecordMagic = float64(franchises.Franchise[i].RecordWins*1) +
-			(float64(franchises.Franchise[i].RecordTies) * 0.5)
+		f := &franchises.Franchise[i]
+		wins, losses, ties := scoredRecord(*f, scoring.Median)
+		games := float64(wins + losses + ties)
+		switch scoring.RecordMetric {
+		case RecordWinPercentage:
+			f.RecordMagic = 0
+			if games > 0 {
+				f.RecordMagic = (float64(wins) + float64(ties)*0.5) / games
+			}
+		case RecordTiesAsLoss:
+			f.RecordMagic = 0
+			if games > 0 {
+				f.RecordMagic = float64(wins) / games
+			}
+		case RecordGamesBack:
+			// Negated so that, like every other metric, bigger is better.
+			f.RecordMagic = (float64(wins-losses) - leaderMargin) / 2
+		default:
+			f.RecordMagic = float64(wins*1) + (float64(ties) * 0.5)
+		}
 	}
 
 	return franchises
 }
 
+// scoredRecord is the record the median mode says to score: head to head, against the weekly
+// median, or both added together.
+func scoredRecord(f Franchise, median MedianMode) (wins, losses, ties int) {
+	switch median {
+	case MedianReplace:
+		return f.MedianWins, f.MedianLosses, f.MedianTies
+	case MedianAugment:
+		return f.RecordWins + f.MedianWins, f.RecordLosses + f.MedianLosses, f.RecordTies + f.MedianTies
+	default:
+		return f.RecordWins, f.RecordLosses, f.RecordTies
+	}
+}
+
 func calculateRecordScore(franchises Franchises) Franchises {
 	for i := 0; i < len(franchises.Franchise); {
 		currentMagicPoints := franchises.Franchise[i].RecordMagic
@@ -433,6 +794,21 @@ func calculateRecordScore(franchises Franchises) Franchises {
 	return franchises
 }
 
+func leagueAPIURL(baseURL, apiKey string) string {
+	return baseURL + LeagueYear + "/" + LeagueAPIPath + LeagueAPIQuery + "&" +
+		LeagueIDQuery + "&" + APIOutputTypeQuery + "&APIKEY=" + apiKey
+}
+
+func leagueStandingsAPIURL(baseURL, apiKey string) string {
+	return baseURL + LeagueYear + "/" + LeagueAPIPath + LeagueStandingsAPIQuery + "&" +
+		LeagueIDQuery + "&" + APIOutputTypeQuery + "&APIKEY=" + apiKey
+}
+
+func allPlayPageURL(baseURL string) string {
+	return baseURL + LeagueYear + "/" + LeagueWebPath + LeagueIDQuery +
+		"&" + PowerRankingsTableQuery + "&" + LeagueOutputSortQuery
+}
+
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
@@ -532,6 +908,7 @@ func associateStandingsWithFranchises(franchiseDetailsResponse LeagueResponse,
 		franchiseStore.Franchise[i].TeamID = franchise.TeamID
 		franchiseStore.Franchise[i].TeamName = franchise.TeamName
 		franchiseStore.Franchise[i].OwnerName = franchise.OwnerName
+		franchiseStore.Franchise[i].Division = franchise.Division
 
 		standing, ok := standingsMap[franchise.TeamID]
 		if !ok {
@@ -547,33 +924,87 @@ func associateStandingsWithFranchises(franchiseDetailsResponse LeagueResponse,
 	return franchiseStore, nil
 }
 
+// unmatchedStandings lists franchises from the league response that have no standings entry.
+func unmatchedStandings(franchiseDetailsResponse LeagueResponse, leagueStandingsResponse LeagueStandingsResponse) []string {
+	standings := make(map[string]bool)
+	for _, franchise := range leagueStandingsResponse.LeagueStandings.Franchise {
+		standings[franchise.TeamID] = true
+	}
+
+	var unmatched []string
+	for _, franchise := range franchiseDetailsResponse.League.Franchises.Franchise {
+		if !standings[franchise.TeamID] {
+			unmatched = append(unmatched, franchise.TeamID)
+		}
+	}
+
+	return unmatched
+}
+
+// copyStandingsDetails parses the standings fields. Points-only leagues leave the head to head
+// fields empty and not every league reports potential points, so a missing value counts as zero; a
+// malformed one is still an error.
 func copyStandingsDetails(franchise, standing Franchise) (Franchise, error) {
 	var err error
 	franchise.RecordWinsString = standing.RecordWinsString
 	franchise.RecordLossesString = standing.RecordLossesString
 	franchise.RecordTiesString = standing.RecordTiesString
 	franchise.PointsForString = standing.PointsForString
+	franchise.PointsAgainstString = standing.PointsAgainstString
+	franchise.PotentialPointsString = standing.PotentialPointsString
 
-	franchise.RecordWins, err = convertStringToInteger(standing.RecordWinsString)
+	franchise.RecordWins, err = convertOptionalInteger(standing.RecordWinsString)
 	if err != nil {
 		return Franchise{}, err
 	}
-	franchise.RecordLosses, err = convertStringToInteger(standing.RecordLossesString)
+	franchise.RecordLosses, err = convertOptionalInteger(standing.RecordLossesString)
 	if err != nil {
 		return Franchise{}, err
 	}
-	franchise.RecordTies, err = convertStringToInteger(standing.RecordTiesString)
+	franchise.RecordTies, err = convertOptionalInteger(standing.RecordTiesString)
 	if err != nil {
 		return Franchise{}, err
 	}
-	franchise.PointsFor, err = strconv.ParseFloat(standing.PointsForString, 64)
+	franchise.PointsFor, err = convertOptionalFloat(standing.PointsForString)
 	if err != nil {
 		return Franchise{}, err
 	}
+	franchise.PointsAgainst, err = convertOptionalFloat(standing.PointsAgainstString)
+	if err != nil {
+		return Franchise{}, err
+	}
+	franchise.PotentialPoints, err = convertOptionalFloat(standing.PotentialPointsString)
+	if err != nil {
+		return Franchise{}, err
+	}
+	franchise.LineupEfficiency = lineupEfficiency(franchise.PointsFor, franchise.PotentialPoints)
 
 	return franchise, nil
 }
 
+// detectLeagueFormat reads the league's h2h setting. Older responses may leave it out, in which case
+// a league where no franchise has a head to head result is taken to be points-only.
+func detectLeagueFormat(league League, leagueStandings LeagueStandings) LeagueFormat {
+	switch strings.ToUpper(league.H2H) {
+	case "NO":
+		return LeaguePointsOnly
+	case "YES":
+		return LeagueHeadToHead
+	}
+
+	for _, franchise := range leagueStandings.Franchise {
+		if franchise.RecordWinsString != "" || franchise.RecordLossesString != "" ||
+			franchise.RecordTiesString != "" {
+			return LeagueHeadToHead
+		}
+	}
+	if len(leagueStandings.Franchise) == 0 {
+		return LeagueHeadToHead
+	}
+
+	return LeaguePointsOnly
+}
+
 func populateHeadToHeadRecords(franchises Franchises) Franchises {
 	for i := 0; i < len(franchises.Franchise); i++ {
 		franchises.Franchise[i].Record =
@@ -594,6 +1025,22 @@ func convertStringToInteger(str string) (int, error) {
 	return integer, nil
 }
 
+func convertOptionalInteger(str string) (int, error) {
+	if str == "" {
+		return 0, nil
+	}
+
+	return convertStringToInteger(str)
+}
+
+func convertOptionalFloat(str string) (float64, error) {
+	if str == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseFloat(str, 64)
+}
+
 func roundFloat(val float64, precision uint) float64 {
 	ratio := math.Pow(10, float64(precision))
 	return math.Round(val*ratio) / ratio
@@ -606,12 +1053,31 @@ const (
 	idleConnTimeout       = 90 * time.Second
 	tlsHandshakeTimeout   = 10 * time.Second
 	expectContinueTimeout = 1 * time.Second
+	scrapeTimeout         = 3 * time.Second
 )
 
-func newCollector() *colly.Collector {
+var (
+	ErrScrapeRequest  = errors.New("AllPlay page request failed")
+	ErrAllPlaySchema  = errors.New("AllPlay table layout changed")
+	ErrNoAllPlayRows  = errors.New("AllPlay table has no team rows")
+	ErrScrapeCanceled = errors.New("AllPlay scrape canceled")
+)
+
+// contextTransport attaches the caller's context to every request the collector makes, since colly
+// has no context support of its own.
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(request.WithContext(t.ctx))
+}
+
+func newCollector(ctx context.Context) *colly.Collector {
 	c := colly.NewCollector()
 
-	c.WithTransport(&http.Transport{
+	c.WithTransport(&contextTransport{ctx: ctx, base: &http.Transport{
 		DialContext: (&net.Dialer{
 			Timeout:   dialerTimeout,
 			KeepAlive: dialerKeepAlive,
@@ -621,43 +1087,67 @@ func newCollector() *colly.Collector {
 		IdleConnTimeout:       idleConnTimeout,
 		TLSHandshakeTimeout:   tlsHandshakeTimeout,
 		ExpectContinueTimeout: expectContinueTimeout,
-	})
+	}})
 
 	return c
 }
 
-func scrape() []AllPlayTeamStats {
+// scrape reads the AllPlay columns from MFL's power rankings page. Errors wrap ErrScrapeCanceled,
+// ErrScrapeRequest, ErrAllPlaySchema or ErrNoAllPlayRows so the caller can decide how to degrade.
+func scrape(ctx context.Context, pageURL string) ([]AllPlayTeamStats, error) {
 	// c := colly.NewCollector(colly.Debugger(&debug.LogDebugger{}))
-	c := newCollector()
+	c := newCollector(ctx)
+	logger := loggerFromContext(ctx).With(slog.String("upstream", "allPlayScrape"))
 
 	var allPlayTeamsStats []AllPlayTeamStats
 
 	c.OnRequest(func(r *colly.Request) {
-		log.Println("Scraping: ", r.URL)
+		logger.Debug("scraping", slog.String("url", r.URL.String()))
 	})
 
 	c.OnResponse(func(r *colly.Response) {
-		fmt.Println("Status: ", r.StatusCode)
+		logger.Debug("scrape response", slog.Int("status", r.StatusCode))
 	})
 
-	c.OnHTML("table.report > tbody", func(h *colly.HTMLElement) {
-		h.ForEach("tr", func(_ int, el *colly.HTMLElement) {
-			allPlayTeamStats := parseRow(el)
-			allPlayTeamsStats = append(allPlayTeamsStats, allPlayTeamStats)
-		})
+	// The page may hold other report tables; only complain if none of them carry AllPlay columns.
+	var tableErr error
+	tableFound := false
+	c.OnHTML("table.report", func(h *colly.HTMLElement) {
+		tableStats, err := parseAllPlayTable(h)
+		if err != nil {
+			tableErr = err
+			return
+		}
+		tableFound = true
+		allPlayTeamsStats = append(allPlayTeamsStats, tableStats...)
 	})
 
-	c.OnError(func(r *colly.Response, err error) {
-		fmt.Println("Request URL:", r.Request.URL, "failed with response:", r, "\nError:", err)
-	})
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrScrapeCanceled, err)
+	}
 
-	err := c.Visit(MflURL + LeagueYear + "/" + LeagueWebPath + LeagueIDQuery +
-		"&" + PowerRankingsTableQuery + "&" + LeagueOutputSortQuery)
-	if err != nil {
-		fmt.Println(err)
+	if err := c.Visit(pageURL); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("%w: %w", ErrScrapeCanceled, ctxErr)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrScrapeRequest, err)
+	}
+
+	if !tableFound {
+		if tableErr != nil {
+			return nil, fmt.Errorf("%w: %w", ErrAllPlaySchema, tableErr)
+		}
+		return nil, fmt.Errorf("%w: no table.report on page", ErrAllPlaySchema)
+	}
+
+	teams := filterTeams(allPlayTeamsStats)
+	if len(teams) == 0 {
+		return nil, ErrNoAllPlayRows
 	}
 
-	return filterTeams(allPlayTeamsStats)
+	logger.Debug("scrape parsed rows", slog.Int("rows", len(teams)))
+
+	return teams, nil
 }
 
 type HTMLElement interface {
@@ -670,17 +1160,160 @@ type HTMLElement interface {
 	Unmarshal(v interface{}) error
 }
 
-func parseRow(h HTMLElement) AllPlayTeamStats {
-	// fmt.Printf("%v", h)
+// AllPlayColumns holds the 1-based positions of the power rankings columns the scraper reads.
+type AllPlayColumns struct {
+	Name       int
+	Wins       int
+	Losses     int
+	Ties       int
+	Percentage int
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9%]+`)
+
+// parseAllPlayTable locates the AllPlay columns from the table's header rows and then reads every
+// body row using those positions.
+func parseAllPlayTable(table HTMLElement) ([]AllPlayTeamStats, error) {
+	columns, err := locateAllPlayColumns(headerLabels(table))
+	if err != nil {
+		return nil, err
+	}
+
+	var allPlayTeamsStats []AllPlayTeamStats
+	table.ForEach("tbody > tr", func(_ int, el *colly.HTMLElement) {
+		allPlayTeamsStats = append(allPlayTeamsStats, parseRow(el, columns))
+	})
+
+	return allPlayTeamsStats, nil
+}
+
+// headerLabels returns one label per column, built from every row of th cells. Grouped headers
+// that span several columns (e.g. "All-Play" over "W L T Pct") are prefixed onto each column, and
+// cells spanning several rows keep their column reserved in the rows below.
+func headerLabels(table HTMLElement) []string {
+	var labels []string
+	reserved := map[int]int{}
+
+	table.ForEach("tr", func(_ int, row *colly.HTMLElement) {
+		column := 0
+		row.ForEach("th", func(_ int, cell *colly.HTMLElement) {
+			for reserved[column] > 0 {
+				column++
+			}
+			colspan := headerSpan(cell.Attr("colspan"))
+			rowspan := headerSpan(cell.Attr("rowspan"))
+			for i := 0; i < colspan; i++ {
+				for column >= len(labels) {
+					labels = append(labels, "")
+				}
+				labels[column] = strings.TrimSpace(labels[column] + " " + strings.TrimSpace(cell.Text))
+				reserved[column] = rowspan
+				column++
+			}
+		})
+
+		for column, rows := range reserved {
+			if rows > 0 {
+				reserved[column] = rows - 1
+			}
+		}
+	})
+
+	return labels
+}
+
+func headerSpan(attr string) int {
+	span, err := strconv.Atoi(attr)
+	if err != nil || span < 1 {
+		return 1
+	}
+
+	return span
+}
+
+// locateAllPlayColumns finds the AllPlay win, loss, tie and percentage columns by label.
+func locateAllPlayColumns(labels []string) (AllPlayColumns, error) {
+	if len(labels) == 0 {
+		return AllPlayColumns{}, errors.New("AllPlay table has no header row")
+	}
+
+	columns := AllPlayColumns{Name: 1}
+	positions := map[string]*int{"w": &columns.Wins, "l": &columns.Losses, "t": &columns.Ties, "pct": &columns.Percentage}
+	suffixes := map[string]string{
+		"w": "w", "win": "w", "wins": "w",
+		"l": "l", "loss": "l", "losses": "l",
+		"t": "t", "tie": "t", "ties": "t",
+		"pct": "pct", "%": "pct", "percent": "pct", "percentage": "pct",
+	}
+
+	for i, label := range labels {
+		normalized := strings.TrimSpace(nonAlphanumeric.ReplaceAllString(strings.ToLower(label), " "))
+		words := strings.Fields(normalized)
+		if len(words) == 0 {
+			continue
+		}
+
+		if words[0] == "franchise" || words[0] == "team" {
+			columns.Name = i + 1
+			continue
+		}
+
+		if !strings.HasPrefix(strings.ReplaceAll(normalized, " ", ""), "allplay") {
+			continue
+		}
+
+		if key, ok := suffixes[words[len(words)-1]]; ok && *positions[key] == 0 {
+			*positions[key] = i + 1
+		}
+	}
+
+	var missing []string
+	for _, key := range []string{"w", "l", "t", "pct"} {
+		if *positions[key] == 0 {
+			missing = append(missing, "AllPlay "+strings.ToUpper(key))
+		}
+	}
+	if len(missing) > 0 {
+		return AllPlayColumns{}, fmt.Errorf("AllPlay table is missing columns %s; headers were %q",
+			strings.Join(missing, ", "), labels)
+	}
+
+	return columns, nil
+}
+
+func parseRow(h HTMLElement, columns AllPlayColumns) AllPlayTeamStats {
 	return AllPlayTeamStats{
-		FranchiseName:     h.ChildText("td:nth-child(1)"),
-		AllPlayWins:       h.ChildText("td:nth-child(13)"),
-		AllPlayLosses:     h.ChildText("td:nth-child(14)"),
-		AllPlayTies:       h.ChildText("td:nth-child(15)"),
-		AllPlayPercentage: h.ChildText("td:nth-child(16)"),
+		FranchiseID:       franchiseIDFromLink(h.ChildAttr(nthChild(columns.Name)+" a", "href")),
+		FranchiseName:     h.ChildText(nthChild(columns.Name)),
+		AllPlayWins:       h.ChildText(nthChild(columns.Wins)),
+		AllPlayLosses:     h.ChildText(nthChild(columns.Losses)),
+		AllPlayTies:       h.ChildText(nthChild(columns.Ties)),
+		AllPlayPercentage: h.ChildText(nthChild(columns.Percentage)),
 	}
 }
 
+// franchiseIDFromLink reads the F parameter of a franchise link such as
+// options?L=15781&F=0003&O=01, or returns "" when there isn't one.
+func franchiseIDFromLink(href string) string {
+	link, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+
+	return link.Query().Get("F")
+}
+
+func nthChild(column int) string {
+	return "td:nth-child(" + strconv.Itoa(column) + ")"
+}
+
+// newHTMLElement wraps a goquery selection so code written against colly elements can run over a
+// document that wasn't fetched by a collector.
+func newHTMLElement(selection *goquery.Selection) *colly.HTMLElement {
+	return colly.NewHTMLElementFromSelectionNode(&colly.Response{Request: &colly.Request{}}, selection,
+		selection.Get(0), 0)
+}
+
 func filterTeams(allPlayTeamsStats []AllPlayTeamStats) []AllPlayTeamStats {
 	var allPlayTeamsStatsReturn []AllPlayTeamStats
 
@@ -696,14 +1329,8 @@ func filterTeams(allPlayTeamsStats []AllPlayTeamStats) []AllPlayTeamStats {
 }
 
 func appendAllPlay(franchises Franchises, allPlayTeamData []AllPlayTeamStats) (Franchises, error) {
-	// Create a map for quick lookup
-	allPlayDataMap := make(map[string]AllPlayTeamStats)
-	for _, data := range allPlayTeamData {
-		allPlayDataMap[data.FranchiseName] = data
-	}
-
 	for i, franchise := range franchises.Franchise {
-		data, ok := allPlayDataMap[franchise.TeamName]
+		data, ok := matchAllPlay(franchise, allPlayTeamData)
 		if !ok {
 			continue
 		}
@@ -716,6 +1343,35 @@ func appendAllPlay(franchises Franchises, allPlayTeamData []AllPlayTeamStats) (F
 	return franchises, nil
 }
 
+// matchAllPlay finds the franchise's row in the scraped AllPlay table, by the franchise ID in the
+// row's link when there is one so a rename can't break the match, and by team name otherwise.
+func matchAllPlay(franchise Franchise, allPlayTeamData []AllPlayTeamStats) (AllPlayTeamStats, bool) {
+	for _, data := range allPlayTeamData {
+		if data.FranchiseID != "" && data.FranchiseID == franchise.TeamID {
+			return data, true
+		}
+	}
+	for _, data := range allPlayTeamData {
+		if data.FranchiseID == "" && data.FranchiseName == franchise.TeamName {
+			return data, true
+		}
+	}
+
+	return AllPlayTeamStats{}, false
+}
+
+// unmatchedAllPlay lists franchises that don't appear in the scraped AllPlay table.
+func unmatchedAllPlay(franchises Franchises, allPlayTeamData []AllPlayTeamStats) []string {
+	var unmatched []string
+	for _, franchise := range franchises.Franchise {
+		if _, ok := matchAllPlay(franchise, allPlayTeamData); !ok {
+			unmatched = append(unmatched, franchise.TeamID)
+		}
+	}
+
+	return unmatched
+}
+
 func updateFranchiseWithAllPlayData(franchise *Franchise, data AllPlayTeamStats) error {
 	var err error
 
@@ -2,13 +2,17 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly"
@@ -49,6 +53,7 @@ func TestCalculateRecordMagic(t *testing.T) {
 	testCases := []struct {
 		name       string
 		franchises Franchises
+		scoring    ScoringConfig
 		expected   Franchises
 	}{
 		{
@@ -68,11 +73,109 @@ func TestCalculateRecordMagic(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "median replaces head to head",
+			franchises: Franchises{
+				Franchise: []Franchise{
+					{RecordWins: 6, RecordTies: 1, MedianWins: 4, MedianTies: 1},
+					{RecordWins: 1, MedianWins: 7},
+				},
+			},
+			scoring: ScoringConfig{Median: MedianReplace},
+			expected: Franchises{
+				Franchise: []Franchise{
+					{RecordMagic: 4.5},
+					{RecordMagic: 7},
+				},
+			},
+		},
+		{
+			name: "median augments head to head",
+			franchises: Franchises{
+				Franchise: []Franchise{
+					{RecordWins: 6, RecordTies: 1, MedianWins: 4, MedianTies: 1},
+					{RecordWins: 1, MedianWins: 7},
+				},
+			},
+			scoring: ScoringConfig{Median: MedianAugment},
+			expected: Franchises{
+				Franchise: []Franchise{
+					{RecordMagic: 11},
+					{RecordMagic: 8},
+				},
+			},
+		},
+		{
+			name: "win percentage with unequal games",
+			franchises: Franchises{
+				Franchise: []Franchise{
+					{RecordWins: 6, RecordLosses: 1, RecordTies: 1},
+					{RecordWins: 3},
+					{},
+				},
+			},
+			scoring: ScoringConfig{RecordMetric: RecordWinPercentage},
+			expected: Franchises{
+				Franchise: []Franchise{
+					{RecordMagic: 0.8125},
+					{RecordMagic: 1},
+					{RecordMagic: 0},
+				},
+			},
+		},
+		{
+			name: "ties as losses",
+			franchises: Franchises{
+				Franchise: []Franchise{
+					{RecordWins: 6, RecordLosses: 1, RecordTies: 1},
+					{RecordWins: 3, RecordLosses: 1},
+				},
+			},
+			scoring: ScoringConfig{RecordMetric: RecordTiesAsLoss},
+			expected: Franchises{
+				Franchise: []Franchise{
+					{RecordMagic: 0.75},
+					{RecordMagic: 0.75},
+				},
+			},
+		},
+		{
+			name: "games back",
+			franchises: Franchises{
+				Franchise: []Franchise{
+					{RecordWins: 6, RecordLosses: 1, RecordTies: 1},
+					{RecordWins: 3},
+					{RecordWins: 2, RecordLosses: 4},
+				},
+			},
+			scoring: ScoringConfig{RecordMetric: RecordGamesBack},
+			expected: Franchises{
+				Franchise: []Franchise{
+					{RecordMagic: 0},
+					{RecordMagic: -1},
+					{RecordMagic: -3.5},
+				},
+			},
+		},
+		{
+			name: "median off",
+			franchises: Franchises{
+				Franchise: []Franchise{
+					{RecordWins: 6, RecordTies: 1, MedianWins: 4, MedianTies: 1},
+				},
+			},
+			scoring: ScoringConfig{Median: MedianOff},
+			expected: Franchises{
+				Franchise: []Franchise{
+					{RecordMagic: 6.5},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := calculateRecordMagic(tc.franchises)
+			result := calculateRecordMagic(tc.franchises, tc.scoring)
 			for i := range result.Franchise {
 				if result.Franchise[i].RecordMagic != tc.expected.Franchise[i].RecordMagic {
 					t.Errorf("Mismatch in test case %s for franchise %d: Expected %f, got %f",
@@ -509,6 +612,12 @@ func TestCopyStandingsDetails(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name:      "Points-only league leaves the record empty",
+			franchise: Franchise{TeamID: "1"},
+			standing:  Franchise{PointsForString: "500.5"},
+			expected:  Franchise{TeamID: "1", PointsForString: "500.5", PointsFor: 500.5},
+		},
 		{
 			name: "Invalid standings details",
 			franchise: Franchise{
@@ -540,6 +649,49 @@ func TestCopyStandingsDetails(t *testing.T) {
 	}
 }
 
+func TestDetectLeagueFormat(t *testing.T) {
+	played := LeagueStandings{Franchise: []Franchise{{RecordWinsString: "3", RecordLossesString: "1"}}}
+	unplayed := LeagueStandings{Franchise: []Franchise{{PointsForString: "500"}}}
+
+	testCases := []struct {
+		name      string
+		h2h       string
+		standings LeagueStandings
+		expected  LeagueFormat
+	}{
+		{name: "head to head", h2h: "YES", standings: unplayed, expected: LeagueHeadToHead},
+		{name: "points only", h2h: "NO", standings: played, expected: LeaguePointsOnly},
+		{name: "unset with records", standings: played, expected: LeagueHeadToHead},
+		{name: "unset without records", standings: unplayed, expected: LeaguePointsOnly},
+		{name: "unset without standings", expected: LeagueHeadToHead},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, detectLeagueFormat(League{H2H: tc.h2h}, tc.standings))
+		})
+	}
+}
+
+func TestScoreChampionshipPointsOnly(t *testing.T) {
+	franchises := Franchises{Franchise: []Franchise{
+		{TeamID: "0001", PointsFor: 100, RecordWins: 3},
+		{TeamID: "0002", PointsFor: 120},
+		{TeamID: "0003", PointsFor: 90, RecordWins: 1},
+	}}
+
+	scored := rankFranchises(scoreChampionship(franchises, ScoringConfig{Format: LeaguePointsOnly}), ScoringConfig{})
+
+	var ids []string
+	for _, f := range scored.Franchise {
+		ids = append(ids, f.TeamID)
+		assert.Zero(t, f.RecordScore, f.TeamID)
+		assert.Empty(t, f.RecordScoreString, f.TeamID)
+		assert.Equal(t, f.PointScore, f.TotalScore, f.TeamID)
+	}
+	assert.Equal(t, []string{"0002", "0001", "0003"}, ids)
+}
+
 func TestPopulateHeadToHeadRecords(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -719,7 +871,7 @@ func TestCheckResponseParity(t *testing.T) {
 }
 
 func TestNewCollector(t *testing.T) {
-	c := newCollector()
+	c := newCollector(context.Background())
 	if c == nil {
 		t.Errorf("newCollector() = %v, want non-nil", c)
 	}
@@ -728,6 +880,81 @@ func TestNewCollector(t *testing.T) {
 	}
 }
 
+func TestScrape(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      int
+		page        string
+		expected    int
+		expectedErr error
+	}{
+		{
+			name:     "team rows",
+			status:   http.StatusOK,
+			page:     allPlayPageHTML(allPlayRowHTML(Team1Name, "10", "5", "0", ".667"), allPlayRowHTML("", "", "", "", "")),
+			expected: 1,
+		},
+		{name: "no team rows", status: http.StatusOK, page: allPlayPageHTML(), expectedErr: ErrNoAllPlayRows},
+		{name: "no report table", status: http.StatusOK, page: "<html><body>maintenance</body></html>", expectedErr: ErrAllPlaySchema},
+		{name: "server error", status: http.StatusInternalServerError, page: "oops", expectedErr: ErrScrapeRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tc.status)
+				_, err := w.Write([]byte(tc.page))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+
+			result, err := scrape(context.Background(), server.URL)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Empty(t, result)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, result, tc.expected)
+		})
+	}
+}
+
+func TestScrapeHonorsCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := scrape(ctx, server.URL)
+	assert.ErrorIs(t, err, ErrScrapeCanceled)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = scrape(ctx, server.URL)
+	assert.ErrorIs(t, err, ErrScrapeCanceled)
+}
+
+func TestShouldDegradeWithoutAllPlay(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.True(t, shouldDegradeWithoutAllPlay(context.Background(), ErrNoAllPlayRows))
+	assert.True(t, shouldDegradeWithoutAllPlay(context.Background(), fmt.Errorf("%w: 500", ErrScrapeRequest)))
+	assert.False(t, shouldDegradeWithoutAllPlay(canceled, ErrScrapeCanceled))
+
+	t.Setenv("ALLPLAY_FAILURE_MODE", "fail")
+	assert.False(t, shouldDegradeWithoutAllPlay(context.Background(), ErrNoAllPlayRows))
+}
+
+func TestFormatWarnings(t *testing.T) {
+	assert.Equal(t, "", formatWarnings(nil))
+	assert.Equal(t, "\n\nWarning: a\n\nWarning: b", formatWarnings([]string{"a", "b"}))
+}
+
 // MockHTMLElement is a mock of colly.HTMLElement.
 type MockHTMLElement struct {
 	mock.Mock
@@ -775,6 +1002,8 @@ func TestParseRow(t *testing.T) {
 	mockHTMLElement := new(MockHTMLElement)
 
 	// Setup expectations
+	mockHTMLElement.On("ChildAttr", "td:nth-child(1) a", "href").
+		Return("https://www46.myfantasyleague.com/2025/options?L=15781&F=0003&O=01")
 	mockHTMLElement.On("ChildText", "td:nth-child(1)").Return("Test Franchise")
 	mockHTMLElement.On("ChildText", "td:nth-child(13)").Return("10")
 	mockHTMLElement.On("ChildText", "td:nth-child(14)").Return("5")
@@ -782,12 +1011,16 @@ func TestParseRow(t *testing.T) {
 	mockHTMLElement.On("ChildText", "td:nth-child(16)").Return("0.66")
 
 	// Call the function with the mock
-	result := parseRow(mockHTMLElement)
+	result := parseRow(mockHTMLElement, AllPlayColumns{Name: 1, Wins: 13, Losses: 14, Ties: 15, Percentage: 16})
 
 	// Assert that the expectations were met
 	mockHTMLElement.AssertExpectations(t)
 
 	// Assert that the result is what you expect
+	if result.FranchiseID != "0003" {
+		t.Errorf("Expected FranchiseID to be '0003', got '%s'", result.FranchiseID)
+	}
+
 	if result.FranchiseName != "Test Franchise" {
 		t.Errorf("Expected FranchiseName to be 'Test Franchise', got '%s'", result.FranchiseName)
 	}
@@ -809,6 +1042,94 @@ func TestParseRow(t *testing.T) {
 	}
 }
 
+func TestLocateAllPlayColumns(t *testing.T) {
+	testCases := []struct {
+		name        string
+		labels      []string
+		expected    AllPlayColumns
+		expectError bool
+	}{
+		{
+			name:     "flat labels",
+			labels:   []string{"Franchise", "Pts", "All-Play W", "All-Play L", "All-Play T", "All-Play Pct"},
+			expected: AllPlayColumns{Name: 1, Wins: 3, Losses: 4, Ties: 5, Percentage: 6},
+		},
+		{
+			name:     "grouped labels",
+			labels:   []string{"Rank", "Team", "AllPlay Wins", "AllPlay Losses", "AllPlay Ties", "AllPlay %"},
+			expected: AllPlayColumns{Name: 2, Wins: 3, Losses: 4, Ties: 5, Percentage: 6},
+		},
+		{
+			name:        "missing percentage",
+			labels:      []string{"Franchise", "All-Play W", "All-Play L", "All-Play T", "Pct"},
+			expectError: true,
+		},
+		{name: "no header", labels: nil, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := locateAllPlayColumns(tc.labels)
+			if (err != nil) != tc.expectError {
+				t.Fatalf("locateAllPlayColumns() error = %v, expectError %v", err, tc.expectError)
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestParseAllPlayTable(t *testing.T) {
+	testCases := []struct {
+		name        string
+		html        string
+		expected    []AllPlayTeamStats
+		expectError string
+	}{
+		{
+			name: "columns found by header",
+			html: allPlayPageHTML(allPlayRowHTML(Team1Name, "10", "5", "1", ".656")),
+			expected: []AllPlayTeamStats{{FranchiseName: Team1Name, AllPlayWins: "10", AllPlayLosses: "5",
+				AllPlayTies: "1", AllPlayPercentage: ".656"}},
+		},
+		{
+			name: "columns moved under a grouped header",
+			html: `<table class="report"><thead>
+				<tr><th rowspan="2">Franchise</th><th colspan="4">All-Play</th><th>Pts</th></tr>
+				<tr><th>W</th><th>L</th><th>T</th><th>Pct</th></tr>
+				</thead><tbody><tr><td>Team 2</td><td>7</td><td>8</td><td>0</td><td>.467</td><td>1500</td></tr></tbody></table>`,
+			expected: []AllPlayTeamStats{{FranchiseName: Team2Name, AllPlayWins: "7", AllPlayLosses: "8",
+				AllPlayTies: "0", AllPlayPercentage: ".467"}},
+		},
+		{
+			name: "AllPlay columns removed",
+			html: `<table class="report"><thead><tr><th>Franchise</th><th>W</th><th>L</th></tr></thead>
+				<tbody><tr><td>Team 1</td><td>1</td><td>2</td></tr></tbody></table>`,
+			expectError: "missing columns AllPlay W, AllPlay L, AllPlay T, AllPlay PCT",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tc.html))
+			if err != nil {
+				t.Fatalf("Failed to parse fixture: %v", err)
+			}
+
+			result, err := parseAllPlayTable(newHTMLElement(doc.Find("table.report")))
+			if tc.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectError) {
+					t.Fatalf("Expected error containing %q, got %v", tc.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAllPlayTable() error = %v", err)
+			}
+			assert.Equal(t, tc.expected, filterTeams(result))
+		})
+	}
+}
+
 func TestFilterTeams(t *testing.T) {
 	// Create a slice of AllPlayTeamStats
 	allPlayTeamsStats := []AllPlayTeamStats{
@@ -945,3 +1266,44 @@ func TestGetLeagueStandings(t *testing.T) {
 		}
 	})
 }
+
+func TestUnmatchedStandings(t *testing.T) {
+	leagueResponse := LeagueResponse{
+		League: League{Franchises: Franchises{Franchise: []Franchise{{TeamID: "1"}, {TeamID: "2"}, {TeamID: "3"}}}},
+	}
+	leagueStandingsResponse := LeagueStandingsResponse{
+		LeagueStandings: LeagueStandings{Franchise: []Franchise{{TeamID: "1"}, {TeamID: "3"}}},
+	}
+
+	assert.Equal(t, []string{"2"}, unmatchedStandings(leagueResponse, leagueStandingsResponse))
+}
+
+func TestUnmatchedAllPlay(t *testing.T) {
+	franchises := Franchises{
+		Franchise: []Franchise{{TeamID: "1", TeamName: Team1Name}, {TeamID: "2", TeamName: Team2Name}},
+	}
+	allPlayTeamData := []AllPlayTeamStats{{FranchiseName: Team1Name}}
+
+	assert.Equal(t, []string{"2"}, unmatchedAllPlay(franchises, allPlayTeamData))
+	assert.Empty(t, unmatchedAllPlay(franchises, append(allPlayTeamData, AllPlayTeamStats{FranchiseName: Team2Name})))
+}
+
+func TestMatchAllPlay(t *testing.T) {
+	renamed := Franchise{TeamID: "0002", TeamName: "New Name"}
+	allPlayTeamData := []AllPlayTeamStats{
+		{FranchiseID: "0001", FranchiseName: "New Name", AllPlayWins: "1"},
+		{FranchiseID: "0002", FranchiseName: "Old Name", AllPlayWins: "2"},
+		{FranchiseName: Team1Name, AllPlayWins: "3"},
+	}
+
+	data, ok := matchAllPlay(renamed, allPlayTeamData)
+	assert.True(t, ok)
+	assert.Equal(t, "2", data.AllPlayWins, "the franchise ID wins over a stale name")
+
+	data, ok = matchAllPlay(Franchise{TeamID: "0003", TeamName: Team1Name}, allPlayTeamData)
+	assert.True(t, ok)
+	assert.Equal(t, "3", data.AllPlayWins, "rows without a link fall back to the name")
+
+	_, ok = matchAllPlay(Franchise{TeamID: "0004", TeamName: "New Name"}, allPlayTeamData)
+	assert.False(t, ok, "a name only matches rows without a franchise ID")
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"sort"
+)
+
+// weeklyMedian is the middle score of the week, or the mean of the middle two when an even number of
+// franchises played.
+func weeklyMedian(scores []float64) float64 {
+	if len(scores) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64{}, scores...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+
+	return (sorted[middle-1] + sorted[middle]) / 2
+}
+
+// playMedianWeek scores one week of games against the week's median: beating it is a win, falling
+// short a loss and matching it a tie. games must all be from the same week.
+func playMedianWeek(franchises []Franchise, index map[string]int, games []Game) {
+	scores := map[string]float64{}
+	for _, game := range games {
+		scores[game.Home] = game.HomeScore
+		scores[game.Away] = game.AwayScore
+	}
+
+	all := make([]float64, 0, len(scores))
+	for _, score := range scores {
+		all = append(all, score)
+	}
+	median := weeklyMedian(all)
+
+	for id, score := range scores {
+		i, ok := index[id]
+		if !ok {
+			continue
+		}
+		switch {
+		case score > median:
+			franchises[i].MedianWins++
+		case score < median:
+			franchises[i].MedianLosses++
+		default:
+			franchises[i].MedianTies++
+		}
+	}
+}
+
+// applyMedianRecords rebuilds each franchise's record against the weekly median from the played
+// games on the schedule.
+func applyMedianRecords(franchises Franchises, games []Game) Franchises {
+	index := map[string]int{}
+	for i := range franchises.Franchise {
+		f := &franchises.Franchise[i]
+		f.MedianWins, f.MedianLosses, f.MedianTies = 0, 0, 0
+		index[f.TeamID] = i
+	}
+
+	for week := 1; week <= latestPlayedWeek(games); week++ {
+		if played := playedGamesInWeek(games, week); len(played) > 0 {
+			playMedianWeek(franchises.Franchise, index, played)
+		}
+	}
+
+	return populateMedianRecords(franchises)
+}
+
+func populateMedianRecords(franchises Franchises) Franchises {
+	for i := range franchises.Franchise {
+		f := &franchises.Franchise[i]
+		f.MedianRecord = formatRecord(f.MedianWins, f.MedianLosses, f.MedianTies)
+	}
+
+	return franchises
+}
+
+// hasMedianRecords reports whether the franchises were scored against the weekly median, which is
+// when the median column is shown.
+func hasMedianRecords(franchises Franchises) bool {
+	for _, franchise := range franchises.Franchise {
+		if franchise.MedianRecord != "" {
+			return true
+		}
+	}
+
+	return false
+}
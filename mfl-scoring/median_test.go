@@ -0,0 +1,128 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeeklyMedian(t *testing.T) {
+	testCases := []struct {
+		name     string
+		scores   []float64
+		expected float64
+	}{
+		{name: "none"},
+		{name: "odd", scores: []float64{90, 110, 100}, expected: 100},
+		{name: "even", scores: []float64{100, 79.5, 90, 80}, expected: 85},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, weeklyMedian(tc.scores))
+		})
+	}
+}
+
+// Against recapGames the week 1 median is 85 (A and B beat it) and the week 2 median is 95 (C beats
+// it, B and D match it), leaving A 1-1-0, B 1-0-1, C 1-1-0 and D 0-1-1.
+func TestApplyMedianRecords(t *testing.T) {
+	franchises := Franchises{Franchise: scenarioFranchises()}
+	franchises.Franchise[0].MedianWins = 9
+
+	records := map[string]string{}
+	for _, franchise := range applyMedianRecords(franchises, recapGames()).Franchise {
+		records[franchise.TeamName] = franchise.MedianRecord
+	}
+
+	assert.Equal(t, map[string]string{"A": "1-1-0", "B": "1-0-1", "C": "1-1-0", "D": "0-1-1"}, records)
+	assert.True(t, hasMedianRecords(franchises))
+	assert.False(t, hasMedianRecords(Franchises{Franchise: scenarioFranchises()}))
+}
+
+func TestStandingsThroughWeekMedian(t *testing.T) {
+	// Fantasy points after week 2: C 190 (4.0), B 185 (3.0), D 174.5 (2.0), A 170 (1.0). Against the
+	// median B is first (4.0), A and C share second and third (2.5 each) and D is last (1.0).
+	standings := standingsThroughWeek(scenarioFranchises(), recapGames(), ScoringConfig{Median: MedianReplace}, 2)
+
+	var order []string
+	var totals []float64
+	for _, franchise := range standings.Franchise {
+		order = append(order, franchise.TeamName)
+		totals = append(totals, franchise.TotalScore)
+	}
+	assert.Equal(t, []string{"B", "C", "A", "D"}, order)
+	assert.Equal(t, []float64{7, 6.5, 3.5, 3}, totals)
+	assert.Equal(t, "1-0-1", standings.Franchise[0].MedianRecord)
+	assert.Equal(t, 1, standings.Franchise[0].RecordTies, "head to head is still kept")
+}
+
+func TestPlayDecidedMedianWeeks(t *testing.T) {
+	games := recapGames()
+	index := map[string]int{}
+	for i, franchise := range scenarioFranchises() {
+		index[franchise.TeamID] = i
+	}
+
+	partial := Franchises{Franchise: scenarioFranchises()}
+	playDecidedMedianWeeks(partial, index, games, []AppliedGameV1{
+		{Week: 3, Winner: "0001", Loser: "0004", WinnerScore: 120, LoserScore: 60},
+	})
+	for _, franchise := range partial.Franchise {
+		assert.Equal(t, "0-0-0", franchise.MedianRecord, "half a week has no median yet")
+	}
+
+	decided := Franchises{Franchise: scenarioFranchises()}
+	playDecidedMedianWeeks(decided, index, games, []AppliedGameV1{
+		{Week: 3, Winner: "0001", Loser: "0004", WinnerScore: 120, LoserScore: 60},
+		{Week: 3, Winner: "0003", Loser: "0002", WinnerScore: 100, LoserScore: 90},
+	})
+	assert.Equal(t, 1, decided.Franchise[index["0001"]].MedianWins)
+	assert.Equal(t, 1, decided.Franchise[index["0003"]].MedianWins)
+	assert.Equal(t, 1, decided.Franchise[index["0002"]].MedianLosses)
+	assert.Equal(t, 1, decided.Franchise[index["0004"]].MedianLosses)
+}
+
+func TestSimulateSeasonMedian(t *testing.T) {
+	franchises := standingsThroughWeek(scenarioFranchises(), simulationGames(), ScoringConfig{Median: MedianAugment},
+		2).Franchise
+
+	projections := simulateSeason(franchises, simulationGames(), ScoringConfig{Median: MedianAugment}, 200, 3)
+	require.Len(t, projections, 4)
+	for _, projection := range projections {
+		var sum float64
+		for _, probability := range projection.PositionProbabilities {
+			sum += probability
+		}
+		assert.InDelta(t, 1, sum, 1e-9, projection.DisplayName)
+	}
+}
+
+func TestMedianColumn(t *testing.T) {
+	standings := testStandings()
+	without := printScoringTableUncouthly(standings.Franchises)
+	assert.NotContains(t, without, "MEDIAN")
+
+	standings.Franchises = populateMedianRecords(standings.Franchises)
+	standings.Franchises.Franchise[0].MedianWins = 2
+	standings.Franchises.Franchise[0].MedianRecord = "2-0-0"
+	assert.Contains(t, printScoringTableUncouthly(standings.Franchises), "MEDIAN W-L-T")
+
+	response := newStandingsResponseV1(standings, DisplayPolicy{Mode: DisplayFull}, standings.FetchedAt)
+	require.NotNil(t, response.Franchises[0].MedianRecord)
+	assert.Equal(t, RecordV1{Wins: 2}, *response.Franchises[0].MedianRecord)
+
+	page := newStandingsPageData(response, DisplayPolicy{Mode: DisplayFull})
+	assert.Equal(t, MedianRecord, page.Columns[3].Label)
+	assert.Equal(t, "2-0-0", page.Rows[0][3].Text)
+}
+
+func TestExplainMedianRecord(t *testing.T) {
+	franchise := Franchise{RecordWins: 3, MedianWins: 2, MedianLosses: 1}
+
+	assert.Equal(t, "3-0-0", describeRecord(franchise, ScoringConfig{Median: MedianOff}))
+	assert.Equal(t, "2-1-0 against the weekly median", describeRecord(franchise, ScoringConfig{Median: MedianReplace}))
+	assert.Equal(t, "3-0-0 head to head and 2-1-0 against the weekly median",
+		describeRecord(franchise, ScoringConfig{Median: MedianAugment}))
+}
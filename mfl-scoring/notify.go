@@ -0,0 +1,408 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+const (
+	WebhookSlack   WebhookKind = "slack"
+	WebhookDiscord WebhookKind = "discord"
+	WebhookJSON    WebhookKind = "json"
+
+	MetricWebhookErrors string = "WebhookErrors"
+
+	// notifyRouteName is the display policy route for scheduled notifications, which have no request
+	// to match domains or stages against.
+	notifyRouteName       string = "/notify"
+	scheduledEventType    string = "Scheduled Event"
+	standingsChangedEvent string = "standings.changed"
+
+	webhookTimeout = 3 * time.Second
+)
+
+type WebhookKind string
+
+// NotificationsConfig says where the last standings snapshot lives and who hears about changes.
+// Snapshot is an s3://bucket/key URL or a local file path.
+type NotificationsConfig struct {
+	Snapshot string          `json:"snapshot"`
+	Webhooks []WebhookConfig `json:"webhooks"`
+}
+
+type WebhookConfig struct {
+	Kind WebhookKind `json:"kind"`
+	URL  string      `json:"url"`
+}
+
+func (c NotificationsConfig) validate() error {
+	if len(c.Webhooks) > 0 && c.Snapshot == "" {
+		return errors.New("notifications need a snapshot location to diff against")
+	}
+
+	for i, hook := range c.Webhooks {
+		switch hook.Kind {
+		case WebhookSlack, WebhookDiscord, WebhookJSON:
+		default:
+			return fmt.Errorf("webhook %d: unknown kind %q", i+1, hook.Kind)
+		}
+
+		parsed, err := url.Parse(hook.URL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return fmt.Errorf("webhook %d: url must be an http(s) URL", i+1)
+		}
+	}
+
+	return nil
+}
+
+// StandingsNotificationV1 is the body posted to json webhooks.
+type StandingsNotificationV1 struct {
+	SchemaVersion string                `json:"schema_version" description:"Version of this payload schema."`
+	Event         string                `json:"event" description:"Always standings.changed."`
+	Metadata      ResponseMetadataV1    `json:"metadata" description:"Where the standings came from and how fresh they are."`
+	Changes       []StandingChangeV1    `json:"changes" description:"Franchises whose place or championship points changed since the last snapshot."`
+	Franchises    []FranchiseStandingV1 `json:"franchises" description:"The new standings in championship order."`
+}
+
+type StandingChangeV1 struct {
+	FranchiseID        string  `json:"franchise_id" description:"MFL franchise ID, e.g. 0003."`
+	DisplayName        string  `json:"display_name" description:"Label to show for the franchise under the display policy."`
+	Rank               int     `json:"rank" description:"Championship position now."`
+	PreviousRank       int     `json:"previous_rank" description:"Championship position in the last snapshot, 0 if the franchise is new."`
+	TotalScore         float64 `json:"total_score" description:"Championship points now."`
+	PreviousTotalScore float64 `json:"previous_total_score" description:"Championship points in the last snapshot."`
+}
+
+// NotificationResult is what a scheduled invocation returns, mostly for the Lambda console.
+type NotificationResult struct {
+	Changes  int  `json:"changes"`
+	Notified int  `json:"notified"`
+	Baseline bool `json:"baseline"`
+}
+
+// snapshotStore keeps the standings the last notification was based on.
+type snapshotStore interface {
+	Load(ctx context.Context) (StandingsResponseV1, bool, error)
+	Save(ctx context.Context, snapshot StandingsResponseV1) error
+}
+
+type fileSnapshotStore struct {
+	path string
+}
+
+func (s fileSnapshotStore) Load(_ context.Context) (StandingsResponseV1, bool, error) {
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return StandingsResponseV1{}, false, nil
+	}
+	if err != nil {
+		return StandingsResponseV1{}, false, err
+	}
+
+	var snapshot StandingsResponseV1
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return StandingsResponseV1{}, false, fmt.Errorf("parsing snapshot %s: %w", s.path, err)
+	}
+
+	return snapshot, true, nil
+}
+
+func (s fileSnapshotStore) Save(_ context.Context, snapshot StandingsResponseV1) error {
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, raw, 0o600)
+}
+
+type s3SnapshotStore struct {
+	client      s3iface.S3API
+	bucket, key string
+}
+
+func (s s3SnapshotStore) Load(ctx context.Context) (StandingsResponseV1, bool, error) {
+	object, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key),
+	})
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey {
+		return StandingsResponseV1{}, false, nil
+	}
+	if err != nil {
+		return StandingsResponseV1{}, false, err
+	}
+	defer object.Body.Close()
+
+	var snapshot StandingsResponseV1
+	if err := json.NewDecoder(object.Body).Decode(&snapshot); err != nil {
+		return StandingsResponseV1{}, false, fmt.Errorf("parsing snapshot s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+
+	return snapshot, true, nil
+}
+
+func (s s3SnapshotStore) Save(ctx context.Context, snapshot StandingsResponseV1) error {
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.key),
+		Body:        bytes.NewReader(raw),
+		ContentType: aws.String("application/json"),
+	})
+
+	return err
+}
+
+// newSnapshotStore picks the store for the configured location.
+func newSnapshotStore(location string) (snapshotStore, error) {
+	if !strings.HasPrefix(location, "s3://") {
+		return fileSnapshotStore{path: location}, nil
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(location, "s3://"), "/")
+	if bucket == "" || key == "" {
+		return nil, fmt.Errorf("snapshot %q must be s3://bucket/key", location)
+	}
+
+	sess, err := session.NewSession()
+	if err != nil {
+		return nil, err
+	}
+
+	return s3SnapshotStore{client: s3.New(sess), bucket: bucket, key: key}, nil
+}
+
+// invoke is the Lambda entry point. EventBridge scheduled events run the notifier; anything else is
+// an API Gateway request.
+func invoke(ctx context.Context, payload json.RawMessage) (any, error) {
+	var event events.CloudWatchEvent
+	if err := json.Unmarshal(payload, &event); err == nil && event.DetailType == scheduledEventType {
+		return handleScheduledEvent(ctx, event)
+	}
+
+	var request events.APIGatewayProxyRequest
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, request)
+}
+
+func handleScheduledEvent(ctx context.Context, event events.CloudWatchEvent) (NotificationResult, error) {
+	logger := slog.Default().With(slog.String("event_id", event.ID), slog.String("source", event.Source))
+	ctx = withLogger(ctx, logger)
+	telemetry := newTelemetry("scheduled", newSpanExporter())
+	ctx = withTelemetry(ctx, telemetry)
+	defer telemetry.Flush(ctx, os.Stdout, os.Getenv(lambdaFunctionNameEnv) != "")
+
+	config, err := currentConfig()
+	if err != nil {
+		return NotificationResult{}, err
+	}
+	if config.Notifications.Snapshot == "" {
+		logger.Warn("scheduled event ignored, no notifications configured")
+		return NotificationResult{}, nil
+	}
+
+	store, err := newSnapshotStore(config.Notifications.Snapshot)
+	if err != nil {
+		return NotificationResult{}, err
+	}
+
+	return notifyStandingsChanges(ctx, notifier{
+		standings: computeStandings,
+		store:     store,
+		client:    &http.Client{Timeout: webhookTimeout},
+		webhooks:  config.Notifications.Webhooks,
+		policy:    config.Display.policyFor(notifyRouteName, events.APIGatewayProxyRequest{}),
+	}, time.Now())
+}
+
+type notifier struct {
+	standings func(ctx context.Context) (Standings, error)
+	store     snapshotStore
+	client    HTTPClient
+	webhooks  []WebhookConfig
+	policy    DisplayPolicy
+}
+
+// notifyStandingsChanges diffs the standings against the stored snapshot and posts any changes.
+// The first run only stores a baseline. The new snapshot is saved even when a webhook fails, so
+// one broken webhook doesn't make the others hear about the same change twice.
+func notifyStandingsChanges(ctx context.Context, n notifier, now time.Time) (NotificationResult, error) {
+	logger := loggerFromContext(ctx)
+
+	standings, err := n.standings(ctx)
+	if err != nil {
+		return NotificationResult{}, err
+	}
+	current := newStandingsResponseV1(standings, n.policy, now)
+
+	previous, found, err := n.store.Load(ctx)
+	if err != nil {
+		return NotificationResult{}, fmt.Errorf("loading snapshot: %w", err)
+	}
+
+	result := NotificationResult{Baseline: !found}
+	var changes []StandingChangeV1
+	if found {
+		changes = diffSnapshots(previous.Franchises, current.Franchises)
+		result.Changes = len(changes)
+	}
+
+	if err := n.store.Save(ctx, current); err != nil {
+		return result, fmt.Errorf("saving snapshot: %w", err)
+	}
+
+	if len(changes) == 0 {
+		logger.Info("standings unchanged", slog.Bool("baseline", result.Baseline))
+		return result, nil
+	}
+
+	notification := StandingsNotificationV1{
+		SchemaVersion: ResponseSchemaVersion,
+		Event:         standingsChangedEvent,
+		Metadata:      current.Metadata,
+		Changes:       changes,
+		Franchises:    current.Franchises,
+	}
+
+	var errs []error
+	for _, hook := range n.webhooks {
+		if err := postWebhook(ctx, n.client, hook, notification); err != nil {
+			telemetryFromContext(ctx).Count(MetricWebhookErrors, 1)
+			logger.Error("webhook failed", slog.String("kind", string(hook.Kind)), slog.Any("error", err))
+			errs = append(errs, err)
+			continue
+		}
+		result.Notified++
+	}
+	logger.Info("standings changes posted", slog.Int("changes", result.Changes),
+		slog.Int("notified", result.Notified))
+
+	return result, errors.Join(errs...)
+}
+
+// diffSnapshots lists the franchises whose place or championship points moved, in current order.
+func diffSnapshots(previous, current []FranchiseStandingV1) []StandingChangeV1 {
+	before := map[string]FranchiseStandingV1{}
+	for _, standing := range previous {
+		before[standing.FranchiseID] = standing
+	}
+
+	var changes []StandingChangeV1
+	for _, standing := range current {
+		old, ok := before[standing.FranchiseID]
+		if ok && old.Rank == standing.Rank && old.TotalScore == standing.TotalScore {
+			continue
+		}
+		changes = append(changes, StandingChangeV1{
+			FranchiseID:        standing.FranchiseID,
+			DisplayName:        standing.DisplayName,
+			Rank:               standing.Rank,
+			PreviousRank:       old.Rank,
+			TotalScore:         standing.TotalScore,
+			PreviousTotalScore: old.TotalScore,
+		})
+	}
+
+	return changes
+}
+
+// notificationText is the chat message for Slack and Discord.
+func notificationText(notification StandingsNotificationV1) string {
+	lines := []string{fmt.Sprintf("Standings update after week %d:", notification.Metadata.Week)}
+	for _, change := range notification.Changes {
+		var move string
+		switch {
+		case change.PreviousRank == 0:
+			move = "enters at " + ordinal(change.Rank)
+		case change.PreviousRank > change.Rank:
+			move = fmt.Sprintf("up %d to %s", change.PreviousRank-change.Rank, ordinal(change.Rank))
+		case change.PreviousRank < change.Rank:
+			move = fmt.Sprintf("down %d to %s", change.Rank-change.PreviousRank, ordinal(change.Rank))
+		default:
+			move = "still " + ordinal(change.Rank)
+		}
+		lines = append(lines, fmt.Sprintf("• %s %s, %s pts (%s)", change.DisplayName, move,
+			formatScore(change.TotalScore), formatChange(change.TotalScore-change.PreviousTotalScore)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// webhookPayload shapes the notification for the kind of webhook.
+func webhookPayload(kind WebhookKind, notification StandingsNotificationV1) any {
+	switch kind {
+	case WebhookSlack:
+		return map[string]string{"text": notificationText(notification)}
+	case WebhookDiscord:
+		return map[string]string{"content": notificationText(notification)}
+	default:
+		return notification
+	}
+}
+
+func postWebhook(ctx context.Context, client HTTPClient, hook WebhookConfig,
+	notification StandingsNotificationV1) error {
+	body, err := json.Marshal(webhookPayload(hook.Kind, notification))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		// Webhook URLs carry their credentials, so keep them out of the error and the logs.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%s webhook: %w", hook.Kind, err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("%s webhook: status %d", hook.Kind, response.StatusCode)
+	}
+
+	return nil
+}
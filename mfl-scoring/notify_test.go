@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookReceiver is a local HTTP server standing in for Slack, Discord and JSON webhooks. It
+// records every body posted to it and answers with status.
+type webhookReceiver struct {
+	*httptest.Server
+	mu     sync.Mutex
+	bodies map[string][]string
+	status int
+}
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	t.Helper()
+	receiver := &webhookReceiver{bodies: map[string][]string{}, status: http.StatusOK}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		receiver.bodies[r.URL.Path] = append(receiver.bodies[r.URL.Path], string(body))
+		status := receiver.status
+		receiver.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(receiver.Close)
+
+	return receiver
+}
+
+func (r *webhookReceiver) received(path string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.bodies[path]...)
+}
+
+func (r *webhookReceiver) webhooks() []WebhookConfig {
+	return []WebhookConfig{
+		{Kind: WebhookSlack, URL: r.URL + "/slack"},
+		{Kind: WebhookDiscord, URL: r.URL + "/discord"},
+		{Kind: WebhookJSON, URL: r.URL + "/json"},
+	}
+}
+
+func TestNotifyStandingsChanges(t *testing.T) {
+	receiver := newWebhookReceiver(t)
+	standings := testStandings()
+	n := notifier{
+		standings: func(context.Context) (Standings, error) { return standings, nil },
+		store:     fileSnapshotStore{path: filepath.Join(t.TempDir(), "snapshots", "standings.json")},
+		client:    &http.Client{},
+		webhooks:  receiver.webhooks(),
+		policy:    DisplayPolicy{Mode: DisplayFull},
+	}
+	now := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+
+	result, err := notifyStandingsChanges(context.Background(), n, now)
+	require.NoError(t, err)
+	assert.Equal(t, NotificationResult{Baseline: true}, result, "first run only stores a baseline")
+	assert.Empty(t, receiver.received("/slack"))
+
+	result, err = notifyStandingsChanges(context.Background(), n, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, NotificationResult{}, result, "nothing changed")
+
+	// Team 2 overtakes Team 1.
+	standings.Franchises.Franchise[0], standings.Franchises.Franchise[1] = standings.Franchises.Franchise[1],
+		standings.Franchises.Franchise[0]
+	standings.Franchises.Franchise[0].TotalScore = 5
+	result, err = notifyStandingsChanges(context.Background(), n, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, NotificationResult{Changes: 2, Notified: 3}, result)
+
+	slack := receiver.received("/slack")
+	require.Len(t, slack, 1)
+	var slackBody map[string]string
+	require.NoError(t, json.Unmarshal([]byte(slack[0]), &slackBody))
+	assert.Contains(t, slackBody["text"], "• "+Team2Name+" up 1 to 1st, 5.0 pts (+3.0)")
+	assert.Contains(t, slackBody["text"], "• "+Team1Name+" down 1 to 2nd, 4.0 pts (0.0)")
+
+	discord := receiver.received("/discord")
+	require.Len(t, discord, 1)
+	var discordBody map[string]string
+	require.NoError(t, json.Unmarshal([]byte(discord[0]), &discordBody))
+	assert.Equal(t, slackBody["text"], discordBody["content"])
+
+	generic := receiver.received("/json")
+	require.Len(t, generic, 1)
+	var notification StandingsNotificationV1
+	require.NoError(t, json.Unmarshal([]byte(generic[0]), &notification))
+	assert.Equal(t, standingsChangedEvent, notification.Event)
+	assert.Equal(t, StandingChangeV1{FranchiseID: "0002", DisplayName: Team2Name, Rank: 1, PreviousRank: 2,
+		TotalScore: 5, PreviousTotalScore: 2}, notification.Changes[0])
+	assert.Len(t, notification.Franchises, 2)
+}
+
+func TestNotifyStandingsChangesWebhookFailure(t *testing.T) {
+	receiver := newWebhookReceiver(t)
+	store := fileSnapshotStore{path: filepath.Join(t.TempDir(), "standings.json")}
+	previous := newStandingsResponseV1(testStandings(), DisplayPolicy{Mode: DisplayFull}, time.Now())
+	previous.Franchises[0].TotalScore = 1
+	require.NoError(t, store.Save(context.Background(), previous))
+
+	receiver.status = http.StatusInternalServerError
+	n := notifier{
+		standings: func(context.Context) (Standings, error) { return testStandings(), nil },
+		store:     store,
+		client:    &http.Client{},
+		webhooks:  receiver.webhooks()[:1],
+		policy:    DisplayPolicy{Mode: DisplayFull},
+	}
+
+	result, err := notifyStandingsChanges(context.Background(), n, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "slack webhook: status 500")
+	assert.NotContains(t, err.Error(), receiver.URL, "webhook URLs are secret")
+	assert.Equal(t, 1, result.Changes)
+	assert.Zero(t, result.Notified)
+
+	saved, found, err := store.Load(context.Background())
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 4.0, saved.Franchises[0].TotalScore, "the snapshot still moves on")
+}
+
+func TestNotifyStandingsChangesStandingsError(t *testing.T) {
+	n := notifier{
+		standings: func(context.Context) (Standings, error) { return Standings{}, errors.New("MFL down") },
+		store:     fileSnapshotStore{path: filepath.Join(t.TempDir(), "standings.json")},
+	}
+
+	_, err := notifyStandingsChanges(context.Background(), n, time.Now())
+	assert.EqualError(t, err, "MFL down")
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	previous := []FranchiseStandingV1{
+		{FranchiseID: "0001", Rank: 1, TotalScore: 8},
+		{FranchiseID: "0002", Rank: 2, TotalScore: 6},
+		{FranchiseID: "0003", Rank: 3, TotalScore: 4},
+	}
+	current := []FranchiseStandingV1{
+		{FranchiseID: "0001", Rank: 1, TotalScore: 8},
+		{FranchiseID: "0003", Rank: 2, TotalScore: 6},
+		{FranchiseID: "0002", Rank: 3, TotalScore: 4},
+		{FranchiseID: "0004", Rank: 4, TotalScore: 2},
+	}
+
+	changes := diffSnapshots(previous, current)
+
+	var ids []string
+	for _, change := range changes {
+		ids = append(ids, change.FranchiseID)
+	}
+	assert.Equal(t, []string{"0003", "0002", "0004"}, ids)
+	assert.Zero(t, changes[2].PreviousRank)
+	assert.Contains(t, notificationText(StandingsNotificationV1{Changes: changes[2:]}), "enters at 4th")
+}
+
+func TestNotificationsConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config NotificationsConfig
+		errMsg string
+	}{
+		{name: "empty"},
+		{name: "valid", config: NotificationsConfig{Snapshot: "s3://bucket/standings.json",
+			Webhooks: []WebhookConfig{{Kind: WebhookSlack, URL: "https://hooks.slack.com/services/x"}}}},
+		{name: "no snapshot", config: NotificationsConfig{
+			Webhooks: []WebhookConfig{{Kind: WebhookJSON, URL: "https://example.com"}}},
+			errMsg: "notifications need a snapshot location to diff against"},
+		{name: "unknown kind", config: NotificationsConfig{Snapshot: "/tmp/s.json",
+			Webhooks: []WebhookConfig{{Kind: "irc", URL: "https://example.com"}}},
+			errMsg: `webhook 1: unknown kind "irc"`},
+		{name: "bad URL", config: NotificationsConfig{Snapshot: "/tmp/s.json",
+			Webhooks: []WebhookConfig{{Kind: WebhookDiscord, URL: "discord.com/api/webhooks"}}},
+			errMsg: "webhook 1: url must be an http(s) URL"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.validate()
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestNewSnapshotStore(t *testing.T) {
+	store, err := newSnapshotStore("/tmp/standings.json")
+	require.NoError(t, err)
+	assert.Equal(t, fileSnapshotStore{path: "/tmp/standings.json"}, store)
+
+	store, err = newSnapshotStore("s3://bucket/mfl/standings.json")
+	require.NoError(t, err)
+	assert.Equal(t, "bucket", store.(s3SnapshotStore).bucket)
+	assert.Equal(t, "mfl/standings.json", store.(s3SnapshotStore).key)
+
+	_, err = newSnapshotStore("s3://bucket")
+	assert.Error(t, err)
+}
+
+func TestInvoke(t *testing.T) {
+	payload, err := json.Marshal(map[string]any{"httpMethod": "GET", "path": "/mfl-scoring/schema/v1"})
+	require.NoError(t, err)
+
+	response, err := invoke(context.Background(), payload)
+	require.NoError(t, err)
+	require.IsType(t, events.APIGatewayProxyResponse{}, response)
+	assert.Equal(t, http.StatusOK, response.(events.APIGatewayProxyResponse).StatusCode)
+
+	// Without notifications configured a scheduled event is a no-op.
+	payload, err = json.Marshal(map[string]any{"id": "1", "source": "aws.events", "detail-type": "Scheduled Event"})
+	require.NoError(t, err)
+
+	response, err = invoke(context.Background(), payload)
+	require.NoError(t, err)
+	assert.Equal(t, NotificationResult{}, response)
+}
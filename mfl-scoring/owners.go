@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+)
+
+// OwnerConfig is one person in the owner registry. Franchise IDs outlive their owners and owners
+// move between franchises, so the registry says who held which franchise in which seasons. The
+// registry's name replaces the owner name MFL reports, and Alias is what the alias and
+// owner_first_name display modes show for the owner.
+type OwnerConfig struct {
+	// ID is the owner's stable identity, e.g. "tkelsch". It is never shown in place of a name.
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	Alias      string        `json:"alias"`
+	Franchises []OwnerTenure `json:"franchises"`
+}
+
+// OwnerTenure is the seasons an owner held a franchise. From and To are inclusive; leave From at 0
+// for since the league began and To at 0 for to this day.
+type OwnerTenure struct {
+	FranchiseID string `json:"franchise_id"`
+	From        int    `json:"from"`
+	To          int    `json:"to"`
+}
+
+func (t OwnerTenure) covers(year int) bool {
+	return year >= t.From && (t.To == 0 || year <= t.To)
+}
+
+func (t OwnerTenure) overlaps(other OwnerTenure) bool {
+	return t.FranchiseID == other.FranchiseID && (t.To == 0 || other.From <= t.To) &&
+		(other.To == 0 || t.From <= other.To)
+}
+
+func validateOwners(owners []OwnerConfig) error {
+	ids := map[string]bool{}
+	var tenures []OwnerTenure
+	var holders []string
+	for i, owner := range owners {
+		if owner.ID == "" {
+			return fmt.Errorf("owner %d: id is required", i+1)
+		}
+		if ids[owner.ID] {
+			return fmt.Errorf("owner %s is listed more than once", owner.ID)
+		}
+		ids[owner.ID] = true
+
+		if len(owner.Franchises) == 0 {
+			return fmt.Errorf("owner %s: no franchises", owner.ID)
+		}
+		for _, tenure := range owner.Franchises {
+			if tenure.FranchiseID == "" {
+				return fmt.Errorf("owner %s: franchise_id is required", owner.ID)
+			}
+			if tenure.To != 0 && tenure.To < tenure.From {
+				return fmt.Errorf("owner %s: franchise %s ends before it starts", owner.ID, tenure.FranchiseID)
+			}
+			for j, other := range tenures {
+				if tenure.overlaps(other) {
+					return fmt.Errorf("owners %s and %s both hold franchise %s in the same season", holders[j],
+						owner.ID, tenure.FranchiseID)
+				}
+			}
+			tenures = append(tenures, tenure)
+			holders = append(holders, owner.ID)
+		}
+	}
+
+	return nil
+}
+
+// ownerFor finds who held the franchise in the season.
+func ownerFor(owners []OwnerConfig, franchiseID string, year int) (OwnerConfig, bool) {
+	for _, owner := range owners {
+		for _, tenure := range owner.Franchises {
+			if tenure.FranchiseID == franchiseID && tenure.covers(year) {
+				return owner, true
+			}
+		}
+	}
+
+	return OwnerConfig{}, false
+}
+
+// applyOwnerRegistry stamps each franchise with the owner who held it in the season. Franchises the
+// registry doesn't cover keep the owner name MFL reported.
+func applyOwnerRegistry(franchises Franchises, owners []OwnerConfig, year int) Franchises {
+	for i := range franchises.Franchise {
+		f := &franchises.Franchise[i]
+		owner, ok := ownerFor(owners, f.TeamID, year)
+		if !ok {
+			continue
+		}
+
+		f.OwnerID = owner.ID
+		f.OwnerAlias = owner.Alias
+		if owner.Name != "" {
+			f.OwnerName = owner.Name
+		}
+	}
+
+	return franchises
+}
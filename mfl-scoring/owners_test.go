@@ -0,0 +1,114 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testOwners has Kim holding 0001 throughout and Lee taking 0002 over from Pat in 2024.
+func testOwners() []OwnerConfig {
+	return []OwnerConfig{
+		{ID: "kim", Name: "Kim Park", Alias: "The Commish", Franchises: []OwnerTenure{{FranchiseID: "0001"}}},
+		{ID: "pat", Name: "Pat Jones", Franchises: []OwnerTenure{{FranchiseID: "0002", To: 2023}}},
+		{ID: "lee", Franchises: []OwnerTenure{{FranchiseID: "0002", From: 2024}}},
+	}
+}
+
+func TestValidateOwners(t *testing.T) {
+	testCases := []struct {
+		name   string
+		owners []OwnerConfig
+		errMsg string
+	}{
+		{name: "none"},
+		{name: "valid", owners: testOwners()},
+		{name: "no id", owners: []OwnerConfig{{Franchises: []OwnerTenure{{FranchiseID: "0001"}}}},
+			errMsg: "owner 1: id is required"},
+		{name: "twice", owners: append(testOwners(), OwnerConfig{ID: "kim"}),
+			errMsg: "owner kim is listed more than once"},
+		{name: "no franchises", owners: []OwnerConfig{{ID: "kim"}}, errMsg: "owner kim: no franchises"},
+		{name: "no franchise id", owners: []OwnerConfig{{ID: "kim", Franchises: []OwnerTenure{{From: 2020}}}},
+			errMsg: "owner kim: franchise_id is required"},
+		{name: "backwards", owners: []OwnerConfig{{ID: "kim",
+			Franchises: []OwnerTenure{{FranchiseID: "0001", From: 2024, To: 2020}}}},
+			errMsg: "owner kim: franchise 0001 ends before it starts"},
+		{name: "overlap", owners: append(testOwners(), OwnerConfig{ID: "sam",
+			Franchises: []OwnerTenure{{FranchiseID: "0002", From: 2023, To: 2023}}}),
+			errMsg: "owners pat and sam both hold franchise 0002 in the same season"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateOwners(tc.owners)
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestOwnerFor(t *testing.T) {
+	testCases := []struct {
+		franchiseID string
+		year        int
+		expected    string
+	}{
+		{franchiseID: "0001", year: 2015, expected: "kim"},
+		{franchiseID: "0002", year: 2023, expected: "pat"},
+		{franchiseID: "0002", year: 2024, expected: "lee"},
+		{franchiseID: "0003", year: 2024},
+	}
+
+	for _, tc := range testCases {
+		owner, ok := ownerFor(testOwners(), tc.franchiseID, tc.year)
+		assert.Equal(t, tc.expected != "", ok, tc.franchiseID)
+		assert.Equal(t, tc.expected, owner.ID, tc.franchiseID)
+	}
+}
+
+func TestApplyOwnerRegistry(t *testing.T) {
+	franchises := applyOwnerRegistry(testStandings().Franchises, testOwners(), 2025).Franchise
+
+	assert.Equal(t, "kim", franchises[0].OwnerID)
+	assert.Equal(t, "Kim Park", franchises[0].OwnerName)
+	assert.Equal(t, "The Commish", franchises[0].OwnerAlias)
+	assert.Equal(t, "lee", franchises[1].OwnerID)
+	assert.Equal(t, Team2Owner, franchises[1].OwnerName, "without a registry name MFL's is kept")
+
+	earlier := applyOwnerRegistry(testStandings().Franchises, testOwners(), 2023).Franchise
+	assert.Equal(t, "Pat Jones", earlier[1].OwnerName)
+}
+
+func TestOwnerRegistryDisplay(t *testing.T) {
+	standings := testStandings()
+	standings.Franchises = applyOwnerRegistry(standings.Franchises, testOwners(), 2025)
+
+	testCases := []struct {
+		mode         DisplayMode
+		displayNames []string
+		ownerIDs     []string
+	}{
+		{mode: DisplayFull, displayNames: []string{Team1Name, Team2Name}, ownerIDs: []string{"kim", "lee"}},
+		{mode: DisplayOwnerFirstName, displayNames: []string{"The Commish", "Owner"}, ownerIDs: []string{"kim", "lee"}},
+		{mode: DisplayAlias, displayNames: []string{"The Commish", "Second"}, ownerIDs: []string{"", ""}},
+		{mode: DisplayTeamID, displayNames: []string{"0001", "0002"}, ownerIDs: []string{"", ""}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			policy := DisplayPolicy{Mode: tc.mode, Aliases: map[string]string{"0001": "First", "0002": "Second"}}
+			response := newStandingsResponseV1(standings, policy, time.Now())
+
+			require.Len(t, response.Franchises, 2)
+			for i, franchise := range response.Franchises {
+				assert.Equal(t, tc.displayNames[i], franchise.DisplayName)
+				assert.Equal(t, tc.ownerIDs[i], franchise.OwnerID)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"embed"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed templates/standings.html.tmpl
+var templateFS embed.FS
+
+var standingsPage = template.Must(template.ParseFS(templateFS, "templates/standings.html.tmpl"))
+
+// pageColumn is one column of the standings page. Tooltip doubles as the explanation in the
+// "How scoring works" list for columns marked Explained.
+type pageColumn struct {
+	Label     string
+	Tooltip   string
+	Type      string
+	Class     string
+	Explained bool
+}
+
+type pageCell struct {
+	Label     string
+	Text      string
+	SortValue string
+	Class     string
+}
+
+type standingsPageData struct {
+	LeagueName string
+	Year       int
+	Week       int
+	DataAsOf   string
+	Warnings   []string
+	Columns    []pageColumn
+	Rows       [][]pageCell
+}
+
+// renderPage renders the standalone standings page. It reads from the v1 response, so the display
+// policy has already removed any hidden names before the template sees them.
+func renderPage(standings Standings, policy DisplayPolicy, now time.Time) (string, error) {
+	data := newStandingsPageData(newStandingsResponseV1(standings, policy, now), policy)
+
+	var b strings.Builder
+	if err := standingsPage.Execute(&b, data); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+func newStandingsPageData(response StandingsResponseV1, policy DisplayPolicy) standingsPageData {
+	var columns []pageColumn
+	if policy.HidesTeamNames() {
+		columns = append(columns, pageColumn{Label: displayLabelHeader(policy.Mode), Tooltip: "Franchise",
+			Type: "text", Class: "name"})
+	} else {
+		columns = append(columns,
+			pageColumn{Label: "Team Name", Tooltip: "Franchise name", Type: "text", Class: "name"},
+			pageColumn{Label: "Owner", Tooltip: "Franchise owner", Type: "text", Class: "name"})
+	}
+	var showMedian, showPointsAgainst, showPotentialPoints bool
+	var showPAScore, showPPScore, showEffScore, showLuck bool
+	for _, franchise := range response.Franchises {
+		showMedian = showMedian || franchise.MedianRecord != nil
+		showPointsAgainst = showPointsAgainst || franchise.PointsAgainst != nil
+		showPotentialPoints = showPotentialPoints || franchise.PotentialPoints != nil
+		showPAScore = showPAScore || franchise.PointsAgainstScore != 0
+		showPPScore = showPPScore || franchise.PotentialPointsScore != 0
+		showEffScore = showEffScore || franchise.EfficiencyScore != 0
+		showLuck = showLuck || franchise.Luck != nil
+	}
+	// Points-only leagues have no head to head record, so the record columns would only show zeros.
+	showRecord := response.Metadata.LeagueFormat != string(LeaguePointsOnly)
+
+	if showRecord {
+		columns = append(columns,
+			pageColumn{Label: Record, Tooltip: "Head to head wins, losses and ties.", Type: "number"})
+	}
+	if showMedian {
+		columns = append(columns, pageColumn{Label: MedianRecord, Tooltip: "Wins, losses and ties against " +
+			"each week's median score.", Type: "number"})
+	}
+	columns = append(columns,
+		pageColumn{Label: FantasyPts, Tooltip: "Total fantasy points scored this season.", Type: "number"})
+	if showPointsAgainst {
+		columns = append(columns, pageColumn{Label: PtsAgainst, Tooltip: "Total fantasy points scored against " +
+			"the team this season.", Type: "number"})
+	}
+	if showPotentialPoints {
+		columns = append(columns,
+			pageColumn{Label: PotentialPts, Tooltip: "Fantasy points the best possible lineup would have scored " +
+				"each week.", Type: "number"},
+			pageColumn{Label: Efficiency, Tooltip: "Fantasy points as a share of potential points.", Type: "number"})
+	}
+	columns = append(columns,
+		pageColumn{Label: PtsScore, Tooltip: "Championship points for fantasy points: the top scorer earns one " +
+			"point per team in the league, the next one fewer, and tied teams split the points for their places.",
+			Type: "number", Explained: true})
+	if showRecord {
+		columns = append(columns, pageColumn{Label: RecScore, Tooltip: "Championship points for head to head " +
+			"record, awarded the same way by " + recordMetricDescriptions[RecordMetric(response.Metadata.RecordMetric)] +
+			". Teams with the same record split the points for their places.", Type: "number", Explained: true})
+	}
+	if showPAScore {
+		columns = append(columns, componentColumn(PAScore, ComponentPointsAgainst))
+	}
+	if showPPScore {
+		columns = append(columns, componentColumn(PPScore, ComponentPotentialPoints))
+	}
+	if showEffScore {
+		columns = append(columns, componentColumn(EffScore, ComponentLineupEfficiency))
+	}
+	totalTooltip := "Points Score plus Record Score"
+	if !showRecord {
+		totalTooltip = "Points Score"
+	}
+	if showPAScore || showPPScore || showEffScore {
+		totalTooltip += " plus the scores for the optional components"
+	}
+	columns = append(columns,
+		pageColumn{Label: TotalPts, Tooltip: totalTooltip + ". Highest total wins the " +
+			"championship; fantasy points, then AllPlay percentage, break ties.", Type: "number", Explained: true},
+		pageColumn{Label: AllPlayRecord, Tooltip: "Record if every team played every other team each week. " +
+			"Its percentage is the second tiebreaker for total points.", Type: "number", Explained: true},
+		pageColumn{Label: AllPlayPct, Tooltip: "AllPlay winning percentage.", Type: "number"},
+	)
+	if showLuck {
+		columns = append(columns, pageColumn{Label: Luck, Tooltip: "Head to head wins less the wins the AllPlay " +
+			"percentage expects from the same games. Positive means a kind schedule.", Type: "number"})
+	}
+
+	data := standingsPageData{
+		LeagueName: response.Metadata.LeagueName,
+		Year:       response.Metadata.Year,
+		Week:       response.Metadata.Week,
+		DataAsOf:   response.Metadata.DataAsOf.Format(time.RFC1123),
+		Warnings:   response.Metadata.Warnings,
+		Columns:    columns,
+	}
+
+	for _, franchise := range response.Franchises {
+		var cells []pageCell
+		if policy.HidesTeamNames() {
+			cells = append(cells, pageCell{Text: franchise.DisplayName, SortValue: franchise.DisplayName, Class: "name"})
+		} else {
+			cells = append(cells,
+				pageCell{Text: franchise.TeamName, SortValue: franchise.TeamName, Class: "name"},
+				pageCell{Text: franchise.OwnerName, SortValue: franchise.OwnerName, Class: "name"})
+		}
+
+		if showRecord {
+			cells = append(cells, recordCell(franchise.Record))
+		}
+		if showMedian {
+			if median := franchise.MedianRecord; median != nil {
+				cells = append(cells, recordCell(*median))
+			} else {
+				cells = append(cells, pageCell{SortValue: "-1"})
+			}
+		}
+		cells = append(cells, pageCell{Text: strconv.FormatFloat(franchise.PointsFor, 'f', 2, 64),
+			SortValue: formatPageNumber(franchise.PointsFor)})
+		if showPointsAgainst {
+			cells = append(cells, optionalCell(franchise.PointsAgainst, formatPoints))
+		}
+		if showPotentialPoints {
+			cells = append(cells, optionalCell(franchise.PotentialPoints, formatPoints),
+				optionalCell(franchise.LineupEfficiency, formatEfficiency))
+		}
+		cells = append(cells, pageCell{Text: strconv.FormatFloat(franchise.PointsScore, 'f', 1, 64),
+			SortValue: formatPageNumber(franchise.PointsScore)})
+		if showRecord {
+			cells = append(cells, pageCell{Text: strconv.FormatFloat(franchise.RecordScore, 'f', 1, 64),
+				SortValue: formatPageNumber(franchise.RecordScore)})
+		}
+		for _, component := range []struct {
+			show  bool
+			score float64
+		}{
+			{showPAScore, franchise.PointsAgainstScore},
+			{showPPScore, franchise.PotentialPointsScore},
+			{showEffScore, franchise.EfficiencyScore},
+		} {
+			if component.show {
+				cells = append(cells, pageCell{Text: formatScore(component.score),
+					SortValue: formatPageNumber(component.score)})
+			}
+		}
+		cells = append(cells, pageCell{Text: strconv.FormatFloat(franchise.TotalScore, 'f', 1, 64),
+			SortValue: formatPageNumber(franchise.TotalScore)})
+
+		if allPlay := franchise.AllPlay; allPlay != nil {
+			cells = append(cells,
+				pageCell{Text: formatRecord(allPlay.Wins, allPlay.Losses, allPlay.Ties),
+					SortValue: formatPageNumber(allPlay.Percentage)},
+				pageCell{Text: strconv.FormatFloat(allPlay.Percentage, 'f', 3, 64),
+					SortValue: formatPageNumber(allPlay.Percentage)})
+		} else {
+			cells = append(cells, pageCell{SortValue: "-1"}, pageCell{SortValue: "-1"})
+		}
+		if showLuck {
+			cells = append(cells, optionalCell(franchise.Luck, formatLuck))
+		}
+
+		for i := range cells {
+			cells[i].Label = columns[i].Label
+		}
+		data.Rows = append(data.Rows, cells)
+	}
+
+	return data
+}
+
+// recordMetricDescriptions finishes the record score tooltip for each record metric.
+var recordMetricDescriptions = map[RecordMetric]string{
+	"":                  "wins plus half a win per tie",
+	RecordHalfWin:       "wins plus half a win per tie",
+	RecordWinPercentage: "winning percentage, with a tie worth half a win",
+	RecordTiesAsLoss:    "winning percentage, with a tie worth no more than a loss",
+	RecordGamesBack:     "games behind the best record",
+}
+
+// componentColumn is the column for an optional championship point component.
+func componentColumn(label string, metric ComponentMetric) pageColumn {
+	return pageColumn{Label: label, Tooltip: "Championship points for " + componentLabel(metric) +
+		", awarded by place the same way as the Points Score.", Type: "number", Explained: true}
+}
+
+// optionalCell shows a figure MFL may not report, sorting missing ones last.
+func optionalCell(value *float64, format func(float64) string) pageCell {
+	if value == nil {
+		return pageCell{SortValue: "-1"}
+	}
+
+	return pageCell{Text: format(*value), SortValue: formatPageNumber(*value)}
+}
+
+func recordCell(record RecordV1) pageCell {
+	return pageCell{Text: formatRecord(record.Wins, record.Losses, record.Ties),
+		SortValue: formatPageNumber(float64(record.Wins) + float64(record.Ties)/2)}
+}
+
+func formatRecord(wins, losses, ties int) string {
+	return strconv.Itoa(wins) + "-" + strconv.Itoa(losses) + "-" + strconv.Itoa(ties)
+}
+
+func formatPageNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
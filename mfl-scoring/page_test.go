@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderPage(t *testing.T) {
+	body, err := renderPage(testStandings(), DisplayPolicy{Mode: DisplayFull}, time.Now())
+
+	assert.NoError(t, err)
+	assert.Contains(t, body, "<title>Test League Championship Standings 2025</title>")
+	assert.Contains(t, body, "through week 12")
+	assert.Contains(t, body, `<p class="warning">Warning: AllPlay data unavailable</p>`)
+	assert.Contains(t, body, `data-label="Team Name" data-sort="Team 1">Team 1</td>`)
+	assert.Contains(t, body, `data-label="W-L-T" data-sort="9">9-3-0</td>`)
+	assert.Contains(t, body, `data-label="AllPlay %" data-sort="0.758">0.758</td>`)
+	assert.Contains(t, body, `title="Points Score plus Record Score.`)
+	assert.Contains(t, body, "@media only screen and (max-width: 600px)")
+}
+
+func TestRenderPageEscapesUpstreamText(t *testing.T) {
+	standings := testStandings()
+	standings.LeagueName = "<b>League</b>"
+	standings.Warnings = []string{"<script>alert(1)</script>"}
+	standings.Franchises.Franchise[0].TeamName = `"><img src=x onerror=alert(1)>`
+
+	body, err := renderPage(standings, DisplayPolicy{Mode: DisplayFull}, time.Now())
+
+	assert.NoError(t, err)
+	assert.NotContains(t, body, "<script>alert")
+	assert.NotContains(t, body, "<img")
+	assert.NotContains(t, body, "<b>League")
+}
+
+func TestNewStandingsPageDataHidesNameColumns(t *testing.T) {
+	policy := DisplayPolicy{Mode: DisplayTeamID}
+	data := newStandingsPageData(newStandingsResponseV1(testStandings(), policy, time.Now()), policy)
+
+	assert.Equal(t, "Team ID", data.Columns[0].Label)
+	assert.Len(t, data.Columns, 9)
+	for _, row := range data.Rows {
+		assert.Len(t, row, len(data.Columns))
+	}
+	assert.Equal(t, "0001", data.Rows[0][0].Text)
+	assert.Equal(t, "", data.Rows[1][6].Text, "missing AllPlay renders blank")
+	assert.Equal(t, "-0.10", data.Rows[0][8].Text)
+	assert.Equal(t, "", data.Rows[1][8].Text, "no luck without AllPlay")
+}
+
+func TestNewStandingsPageDataPointsOnly(t *testing.T) {
+	standings := testStandings()
+	standings.Scoring.Format = LeaguePointsOnly
+	for i := range standings.Franchises.Franchise {
+		franchise := &standings.Franchises.Franchise[i]
+		franchise.RecordWins, franchise.RecordLosses, franchise.RecordTies = 0, 0, 0
+		franchise.RecordScore = 0
+	}
+	policy := DisplayPolicy{Mode: DisplayFull}
+
+	data := newStandingsPageData(newStandingsResponseV1(standings, policy, time.Now()), policy)
+
+	for _, column := range data.Columns {
+		assert.NotEqual(t, Record, column.Label)
+		assert.NotEqual(t, RecScore, column.Label)
+	}
+	for _, row := range data.Rows {
+		assert.Len(t, row, len(data.Columns))
+	}
+	body, err := renderPage(standings, policy, time.Now())
+	assert.NoError(t, err)
+	assert.NotContains(t, body, "0-0-0")
+	assert.Contains(t, body, `title="Points Score. Highest total wins`)
+}
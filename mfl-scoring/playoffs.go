@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// PlayoffsConfig is the league's playoff format. The top Teams franchises in the championship table
+// qualify, and the top Byes seeds skip the first round. Without Byes, the top seeds get just enough
+// byes to fill a bracket whose size is a power of two, so 6 teams give seeds 1 and 2 a bye.
+// DivisionWinnersFirst seeds every division winner ahead of the other qualifiers, still in
+// championship order.
+type PlayoffsConfig struct {
+	Teams                int  `json:"teams"`
+	Byes                 *int `json:"byes"`
+	DivisionWinnersFirst bool `json:"division_winners_first"`
+}
+
+func defaultPlayoffsConfig() PlayoffsConfig {
+	return PlayoffsConfig{Teams: 4}
+}
+
+func (c PlayoffsConfig) validate() error {
+	if c.Teams < 2 {
+		return errors.New("playoffs teams must be at least 2")
+	}
+
+	if c.Byes == nil {
+		return nil
+	}
+	if *c.Byes < 0 {
+		return errors.New("playoffs byes can't be negative")
+	}
+	if *c.Byes > c.Teams-2 {
+		return errors.New("playoffs byes must leave at least 2 teams in the first round")
+	}
+	if (c.Teams-*c.Byes)%2 != 0 {
+		return errors.New("playoffs byes must leave an even number of teams in the first round")
+	}
+
+	return nil
+}
+
+// byesFor is how many top seeds skip the first round of a field of teams. A league smaller than
+// the configured field falls back to the derived byes when the configured ones no longer fit.
+func (c PlayoffsConfig) byesFor(teams int) int {
+	if c.Byes != nil && *c.Byes <= teams-2 && (teams-*c.Byes)%2 == 0 {
+		return *c.Byes
+	}
+
+	return bracketSize(teams) - teams
+}
+
+type PlayoffsResponseV1 struct {
+	SchemaVersion        string             `json:"schema_version" description:"Version of this response schema."`
+	Metadata             ResponseMetadataV1 `json:"metadata" description:"Where the standings came from and how fresh they are."`
+	Teams                int                `json:"teams" description:"Franchises in the playoffs."`
+	Byes                 int                `json:"byes" description:"Top seeds that skip the first round."`
+	DivisionWinnersFirst bool               `json:"division_winners_first" description:"Whether division winners are seeded ahead of the other qualifiers."`
+	Seeds                []PlayoffSeedV1    `json:"seeds" description:"Qualifiers from the top seed down, as the standings are now."`
+	Rounds               []PlayoffRoundV1   `json:"rounds" description:"The bracket, first round first."`
+}
+
+type PlayoffSeedV1 struct {
+	Seed           int     `json:"seed" description:"Playoff seed, 1 is the top seed."`
+	FranchiseID    string  `json:"franchise_id" description:"MFL franchise ID, e.g. 0003."`
+	DisplayName    string  `json:"display_name" description:"Label to show for the franchise under the display policy."`
+	Rank           int     `json:"rank" description:"Championship position."`
+	TotalScore     float64 `json:"total_score" description:"Championship points."`
+	DivisionWinner bool    `json:"division_winner,omitempty" description:"Whether the franchise leads its division."`
+	Bye            bool    `json:"bye,omitempty" description:"Whether the seed skips the first round."`
+}
+
+type PlayoffRoundV1 struct {
+	Round int             `json:"round" description:"Round number, 1 is the first round."`
+	Name  string          `json:"name" description:"Round name, e.g. Semifinals."`
+	Games []PlayoffGameV1 `json:"games" description:"Games in bracket order, top to bottom."`
+}
+
+type PlayoffGameV1 struct {
+	Game   int           `json:"game" description:"Game number, counted across the whole bracket."`
+	Top    PlayoffSlotV1 `json:"top" description:"Upper slot of the game in the bracket."`
+	Bottom PlayoffSlotV1 `json:"bottom" description:"Lower slot of the game in the bracket."`
+}
+
+// PlayoffSlotV1 is either a known seed or the winner of an earlier game.
+type PlayoffSlotV1 struct {
+	Seed     int    `json:"seed,omitempty" description:"Seed in the slot. Omitted until the earlier game is played."`
+	WinnerOf int    `json:"winner_of,omitempty" description:"Game whose winner takes the slot. Omitted for a known seed."`
+	Label    string `json:"label" description:"The seed's display name, or which game's winner it is."`
+}
+
+// playoffSeeds picks and orders the qualifiers. franchises must be in championship order.
+func playoffSeeds(franchises []Franchise, config PlayoffsConfig) []PlayoffSeedV1 {
+	ranks := map[string]int{}
+	for i, franchise := range franchises {
+		ranks[franchise.TeamID] = i + 1
+	}
+
+	ordered := franchises
+	if config.DivisionWinnersFirst {
+		ordered = make([]Franchise, 0, len(franchises))
+		for _, franchise := range franchises {
+			if franchise.DivisionWinner {
+				ordered = append(ordered, franchise)
+			}
+		}
+		for _, franchise := range franchises {
+			if !franchise.DivisionWinner {
+				ordered = append(ordered, franchise)
+			}
+		}
+	}
+
+	teams := min(config.Teams, len(ordered))
+	byes := config.byesFor(teams)
+	seeds := make([]PlayoffSeedV1, 0, teams)
+	for i, franchise := range ordered[:teams] {
+		seeds = append(seeds, PlayoffSeedV1{
+			Seed:           i + 1,
+			FranchiseID:    franchise.TeamID,
+			DisplayName:    displayLabel(franchise),
+			Rank:           ranks[franchise.TeamID],
+			TotalScore:     franchise.TotalScore,
+			DivisionWinner: franchise.DivisionWinner,
+			Bye:            i < byes,
+		})
+	}
+
+	return seeds
+}
+
+// bracketSize is the smallest power of two that fits the teams.
+func bracketSize(teams int) int {
+	size := 1
+	for size < teams {
+		size *= 2
+	}
+
+	return size
+}
+
+// bracketOrder lists the seeds of a bracket of the given size from top to bottom, so the best seeds
+// can only meet in the latest rounds: 1 8 4 5 2 7 3 6 for eight.
+func bracketOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, len(order)*2+1-seed)
+		}
+		order = next
+	}
+
+	return order
+}
+
+// playoffBracket lays out every round. Seeds without a bye meet in the first round, best against
+// worst, and each winner takes the place of the better seed in the game. The bye seeds and the
+// winners then fill a standard bracket, where slots beyond the field are byes: their opponent goes
+// straight through to the next round.
+func playoffBracket(seeds []PlayoffSeedV1) []PlayoffRoundV1 {
+	if len(seeds) < 2 {
+		return nil
+	}
+
+	var entrants []*PlayoffSlotV1
+	firstRound := map[int][2]PlayoffSlotV1{}
+	for _, seed := range seeds {
+		if seed.Bye {
+			entrants = append(entrants, &PlayoffSlotV1{Seed: seed.Seed, Label: seedLabel(seed)})
+		}
+	}
+	playing := seeds[len(entrants):]
+	for i := 0; i < len(playing)/2; i++ {
+		top, bottom := playing[i], playing[len(playing)-1-i]
+		firstRound[len(entrants)] = [2]PlayoffSlotV1{{Seed: top.Seed, Label: seedLabel(top)},
+			{Seed: bottom.Seed, Label: seedLabel(bottom)}}
+		entrants = append(entrants, nil)
+	}
+
+	// The first round games are numbered in the order their winners appear in the bracket.
+	order := bracketOrder(bracketSize(len(entrants)))
+	gameNumber := 0
+	var first PlayoffRoundV1
+	for _, entrant := range order {
+		game, ok := firstRound[entrant-1]
+		if !ok {
+			continue
+		}
+		gameNumber++
+		first.Games = append(first.Games, PlayoffGameV1{Game: gameNumber, Top: game[0], Bottom: game[1]})
+		entrants[entrant-1] = winnerOf(gameNumber)
+	}
+	rounds := []PlayoffRoundV1{first}
+
+	slots := make([]*PlayoffSlotV1, 0, len(order))
+	for _, entrant := range order {
+		if entrant > len(entrants) {
+			slots = append(slots, nil)
+			continue
+		}
+		slots = append(slots, entrants[entrant-1])
+	}
+
+	for len(slots) > 1 {
+		var round PlayoffRoundV1
+		var advancing []*PlayoffSlotV1
+		for i := 0; i < len(slots); i += 2 {
+			top, bottom := slots[i], slots[i+1]
+			if top == nil || bottom == nil {
+				if top == nil {
+					top = bottom
+				}
+				advancing = append(advancing, top)
+				continue
+			}
+
+			gameNumber++
+			round.Games = append(round.Games, PlayoffGameV1{Game: gameNumber, Top: *top, Bottom: *bottom})
+			advancing = append(advancing, winnerOf(gameNumber))
+		}
+		rounds = append(rounds, round)
+		slots = advancing
+	}
+
+	for i := range rounds {
+		rounds[i].Round = i + 1
+		rounds[i].Name = roundName(i+1, len(rounds))
+	}
+
+	return rounds
+}
+
+func winnerOf(game int) *PlayoffSlotV1 {
+	return &PlayoffSlotV1{WinnerOf: game, Label: "Winner of game " + strconv.Itoa(game)}
+}
+
+func seedLabel(seed PlayoffSeedV1) string {
+	return "(" + strconv.Itoa(seed.Seed) + ") " + seed.DisplayName
+}
+
+// roundName names a round by how far it is from the final.
+func roundName(round, rounds int) string {
+	switch rounds - round {
+	case 0:
+		return "Final"
+	case 1:
+		return "Semifinals"
+	case 2:
+		return "Quarterfinals"
+	default:
+		return "Round " + strconv.Itoa(round)
+	}
+}
+
+func printPlayoffs(seeds []PlayoffSeedV1, rounds []PlayoffRoundV1) string {
+	t := table.NewWriter()
+	t.SetOutputMirror(&bytes.Buffer{})
+	t.AppendHeader(table.Row{"Seed", "Team", "Rank", "Total Pts", "Bye"})
+	for _, seed := range seeds {
+		name := seed.DisplayName
+		if seed.DivisionWinner {
+			name += " *"
+		}
+		bye := ""
+		if seed.Bye {
+			bye = "yes"
+		}
+		t.AppendRow(table.Row{seed.Seed, name, ordinal(seed.Rank), formatScore(seed.TotalScore), bye})
+	}
+
+	var b strings.Builder
+	b.WriteString(t.Render())
+	for _, round := range rounds {
+		b.WriteString("\n\n" + round.Name)
+		for _, game := range round.Games {
+			fmt.Fprintf(&b, "\n  Game %d: %s vs %s", game.Game, game.Top.Label, game.Bottom.Label)
+		}
+	}
+
+	return b.String()
+}
+
+func servePlayoffs(ctx context.Context, request events.APIGatewayProxyRequest,
+	_ map[string]string) (events.APIGatewayProxyResponse, error) {
+	format, err := jsonOrTextFormat(request)
+	if err != nil {
+		return textResponse(http.StatusNotAcceptable, err.Error()), nil
+	}
+
+	standings, policy, err := standingsForRoute(ctx, "/playoffs", request)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	config, err := currentConfig()
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	seeds := playoffSeeds(applyDisplayPolicy(standings.Franchises, policy).Franchise, config.Playoffs)
+	rounds := playoffBracket(seeds)
+	if format == FormatJSON {
+		return jsonResponse(http.StatusOK, PlayoffsResponseV1{
+			SchemaVersion:        ResponseSchemaVersion,
+			Metadata:             newStandingsResponseV1(standings, policy, time.Now()).Metadata,
+			Teams:                len(seeds),
+			Byes:                 config.Playoffs.byesFor(len(seeds)),
+			DivisionWinnersFirst: config.Playoffs.DivisionWinnersFirst,
+			Seeds:                seeds,
+			Rounds:               rounds,
+		}, "application/json")
+	}
+
+	return textResponse(http.StatusOK, printPlayoffs(seeds, rounds)+formatWarnings(standings.Warnings)), nil
+}
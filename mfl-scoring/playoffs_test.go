@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// playoffFranchises is eight franchises in championship order. 0004 and 0007 lead their divisions.
+func playoffFranchises() []Franchise {
+	ids := []string{"0001", "0002", "0003", "0004", "0005", "0006", "0007", "0008"}
+	franchises := make([]Franchise, 0, len(ids))
+	for i, id := range ids {
+		franchises = append(franchises, Franchise{TeamID: id, DisplayName: "Team " + id,
+			TotalScore: float64(len(ids) - i), DivisionWinner: id == "0004" || id == "0007"})
+	}
+
+	return franchises
+}
+
+func seedIDs(seeds []PlayoffSeedV1) []string {
+	ids := make([]string, 0, len(seeds))
+	for _, seed := range seeds {
+		ids = append(ids, seed.FranchiseID)
+	}
+
+	return ids
+}
+
+func TestPlayoffSeeds(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   PlayoffsConfig
+		expected []string
+		byes     int
+	}{
+		{name: "top four", config: PlayoffsConfig{Teams: 4}, expected: []string{"0001", "0002", "0003", "0004"}},
+		{name: "six with byes", config: PlayoffsConfig{Teams: 6},
+			expected: []string{"0001", "0002", "0003", "0004", "0005", "0006"}, byes: 2},
+		{name: "division winners first", config: PlayoffsConfig{Teams: 4, DivisionWinnersFirst: true},
+			expected: []string{"0004", "0007", "0001", "0002"}},
+		{name: "more teams than the league", config: PlayoffsConfig{Teams: 12},
+			expected: []string{"0001", "0002", "0003", "0004", "0005", "0006", "0007", "0008"}},
+		{name: "configured byes", config: PlayoffsConfig{Teams: 8, Byes: intPtr(2)},
+			expected: []string{"0001", "0002", "0003", "0004", "0005", "0006", "0007", "0008"}, byes: 2},
+		{name: "configured byes don't fit the league", config: PlayoffsConfig{Teams: 11, Byes: intPtr(5)},
+			expected: []string{"0001", "0002", "0003", "0004", "0005", "0006", "0007", "0008"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			seeds := playoffSeeds(playoffFranchises(), tc.config)
+
+			assert.Equal(t, tc.expected, seedIDs(seeds))
+			byes := 0
+			for _, seed := range seeds {
+				if seed.Bye {
+					byes++
+				}
+			}
+			assert.Equal(t, tc.byes, byes)
+		})
+	}
+
+	seeds := playoffSeeds(playoffFranchises(), PlayoffsConfig{Teams: 4, DivisionWinnersFirst: true})
+	assert.Equal(t, PlayoffSeedV1{Seed: 2, FranchiseID: "0007", DisplayName: "Team 0007", Rank: 7, TotalScore: 2,
+		DivisionWinner: true}, seeds[1])
+}
+
+func TestBracketOrder(t *testing.T) {
+	assert.Equal(t, []int{1, 2}, bracketOrder(2))
+	assert.Equal(t, []int{1, 4, 2, 3}, bracketOrder(4))
+	assert.Equal(t, []int{1, 8, 4, 5, 2, 7, 3, 6}, bracketOrder(8))
+}
+
+func TestPlayoffBracket(t *testing.T) {
+	seeds := playoffSeeds(playoffFranchises(), PlayoffsConfig{Teams: 6})
+
+	rounds := playoffBracket(seeds)
+
+	require.Len(t, rounds, 3)
+	assert.Equal(t, "Quarterfinals", rounds[0].Name)
+	require.Len(t, rounds[0].Games, 2, "seeds 1 and 2 have byes")
+	assert.Equal(t, PlayoffGameV1{Game: 1,
+		Top:    PlayoffSlotV1{Seed: 4, Label: "(4) Team 0004"},
+		Bottom: PlayoffSlotV1{Seed: 5, Label: "(5) Team 0005"}}, rounds[0].Games[0])
+	assert.Equal(t, 3, rounds[0].Games[1].Top.Seed)
+	assert.Equal(t, 6, rounds[0].Games[1].Bottom.Seed)
+
+	assert.Equal(t, "Semifinals", rounds[1].Name)
+	require.Len(t, rounds[1].Games, 2)
+	assert.Equal(t, PlayoffGameV1{Game: 3,
+		Top:    PlayoffSlotV1{Seed: 1, Label: "(1) Team 0001"},
+		Bottom: PlayoffSlotV1{WinnerOf: 1, Label: "Winner of game 1"}}, rounds[1].Games[0])
+	assert.Equal(t, 2, rounds[1].Games[1].Top.Seed)
+	assert.Equal(t, 2, rounds[1].Games[1].Bottom.WinnerOf)
+
+	assert.Equal(t, "Final", rounds[2].Name)
+	assert.Equal(t, PlayoffGameV1{Game: 5,
+		Top:    PlayoffSlotV1{WinnerOf: 3, Label: "Winner of game 3"},
+		Bottom: PlayoffSlotV1{WinnerOf: 4, Label: "Winner of game 4"}}, rounds[2].Games[0])
+
+	assert.Nil(t, playoffBracket(seeds[:1]))
+}
+
+func TestPlayoffBracketConfiguredByes(t *testing.T) {
+	seeds := playoffSeeds(playoffFranchises(), PlayoffsConfig{Teams: 8, Byes: intPtr(2)})
+
+	rounds := playoffBracket(seeds)
+
+	names := make([]string, 0, len(rounds))
+	for _, round := range rounds {
+		names = append(names, round.Name)
+	}
+	assert.Equal(t, []string{"Round 1", "Quarterfinals", "Semifinals", "Final"}, names)
+	require.Len(t, rounds[0].Games, 3, "seeds 1 and 2 sit out")
+	assert.Equal(t, []int{4, 7}, []int{rounds[0].Games[0].Top.Seed, rounds[0].Games[0].Bottom.Seed})
+	assert.Equal(t, []int{5, 6}, []int{rounds[0].Games[1].Top.Seed, rounds[0].Games[1].Bottom.Seed})
+	assert.Equal(t, []int{3, 8}, []int{rounds[0].Games[2].Top.Seed, rounds[0].Games[2].Bottom.Seed})
+	require.Len(t, rounds[1].Games, 1, "five teams left, so the top three move straight on")
+	assert.Equal(t, []int{1, 2}, []int{rounds[1].Games[0].Top.WinnerOf, rounds[1].Games[0].Bottom.WinnerOf})
+	assert.Equal(t, PlayoffGameV1{Game: 5,
+		Top:    PlayoffSlotV1{Seed: 1, Label: "(1) Team 0001"},
+		Bottom: PlayoffSlotV1{WinnerOf: 4, Label: "Winner of game 4"}}, rounds[2].Games[0])
+	assert.Equal(t, []int{2, 3}, []int{rounds[2].Games[1].Top.Seed, rounds[2].Games[1].Bottom.WinnerOf})
+	assert.Equal(t, 7, rounds[3].Games[0].Game)
+
+	final := playoffBracket(playoffSeeds(playoffFranchises()[:2], PlayoffsConfig{Teams: 8, Byes: intPtr(2)}))
+	require.Len(t, final, 1)
+	assert.Equal(t, "Final", final[0].Name)
+}
+
+func TestPrintPlayoffs(t *testing.T) {
+	seeds := playoffSeeds(playoffFranchises(), PlayoffsConfig{Teams: 3, DivisionWinnersFirst: true})
+
+	text := printPlayoffs(seeds, playoffBracket(seeds))
+
+	assert.Contains(t, text, "BYE")
+	assert.Contains(t, text, "Team 0004 *")
+	assert.Contains(t, text, "Semifinals\n  Game 1: (2) Team 0007 vs (3) Team 0001")
+	assert.Contains(t, text, "Final\n  Game 2: (1) Team 0004 vs Winner of game 1")
+}
+
+func TestPlayoffsConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config PlayoffsConfig
+		errMsg string
+	}{
+		{name: "default", config: defaultPlayoffsConfig()},
+		{name: "byes", config: PlayoffsConfig{Teams: 8, Byes: intPtr(2)}},
+		{name: "no byes", config: PlayoffsConfig{Teams: 6, Byes: intPtr(0)}},
+		{name: "one team", config: PlayoffsConfig{Teams: 1}, errMsg: "playoffs teams must be at least 2"},
+		{name: "negative byes", config: PlayoffsConfig{Teams: 4, Byes: intPtr(-1)},
+			errMsg: "playoffs byes can't be negative"},
+		{name: "everyone has a bye", config: PlayoffsConfig{Teams: 4, Byes: intPtr(3)},
+			errMsg: "playoffs byes must leave at least 2 teams in the first round"},
+		{name: "odd first round", config: PlayoffsConfig{Teams: 6, Byes: intPtr(1)},
+			errMsg: "playoffs byes must leave an even number of teams in the first round"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.validate()
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.errMsg)
+		})
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestServePlayoffsRejectsUnsupportedFormatBeforeFetching(t *testing.T) {
+	response, err := servePlayoffs(context.Background(), events.APIGatewayProxyRequest{
+		QueryStringParameters: map[string]string{"output": "csv"},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotAcceptable, response.StatusCode)
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// PowerRankingsConfig tunes the power rankings. Each factor is rated from 0 for the league's worst
+// to 1 for its best, and the power score is the weighted average of the ratings, so the weights
+// only matter relative to each other. A weight of 0 leaves its factor out.
+type PowerRankingsConfig struct {
+	// RecentWeeks is how many of a franchise's latest games count towards its recent form.
+	RecentWeeks int                 `json:"recent_weeks"`
+	Weights     PowerRankingWeights `json:"weights"`
+}
+
+type PowerRankingWeights struct {
+	RecentForm float64 `json:"recent_form" description:"Weight of the mean score over the latest games."`
+	PointsFor  float64 `json:"points_for" description:"Weight of the mean score over the season."`
+	AllPlay    float64 `json:"all_play" description:"Weight of the season AllPlay percentage."`
+	Record     float64 `json:"record" description:"Weight of the head to head winning percentage."`
+}
+
+// defaultPowerRankingsConfig leans on scoring, recent scoring most, over results.
+func defaultPowerRankingsConfig() PowerRankingsConfig {
+	return PowerRankingsConfig{
+		RecentWeeks: 3,
+		Weights:     PowerRankingWeights{RecentForm: 0.35, PointsFor: 0.25, AllPlay: 0.25, Record: 0.15},
+	}
+}
+
+func (c PowerRankingsConfig) validate() error {
+	if c.RecentWeeks < 1 {
+		return errors.New("power_rankings recent_weeks must be at least 1")
+	}
+
+	w := c.Weights
+	for _, weight := range []float64{w.RecentForm, w.PointsFor, w.AllPlay, w.Record} {
+		if weight < 0 {
+			return errors.New("power_rankings weights can't be negative")
+		}
+	}
+	if w.RecentForm+w.PointsFor+w.AllPlay+w.Record == 0 {
+		return errors.New("power_rankings needs at least one weight above 0")
+	}
+
+	return nil
+}
+
+type PowerRankingsResponseV1 struct {
+	SchemaVersion string              `json:"schema_version" description:"Version of this response schema."`
+	Metadata      ResponseMetadataV1  `json:"metadata" description:"Where the standings came from and how fresh they are."`
+	RecentWeeks   int                 `json:"recent_weeks" description:"How many of each franchise's latest games make up its recent form."`
+	Weights       PowerRankingWeights `json:"weights" description:"Relative weight of each factor in the power score."`
+	Franchises    []PowerRankingV1    `json:"franchises" description:"Franchises from the highest power score to the lowest."`
+}
+
+// PowerRankingV1 is a franchise's power score and the weekly results it is built from.
+type PowerRankingV1 struct {
+	Rank              int      `json:"rank" description:"Power ranking, 1 is the strongest."`
+	StandingsRank     int      `json:"standings_rank" description:"Current championship position, for comparison."`
+	FranchiseID       string   `json:"franchise_id" description:"MFL franchise ID, e.g. 0003."`
+	DisplayName       string   `json:"display_name" description:"Label to show for the franchise under the display policy."`
+	PowerScore        float64  `json:"power_score" description:"Weighted average of the factor ratings, from 0 to 100."`
+	RecentForm        float64  `json:"recent_form" description:"Mean score over the franchise's latest games."`
+	PointsFor         float64  `json:"points_for" description:"Mean weekly score over the season."`
+	AllPlayPercentage float64  `json:"all_play_percentage" description:"AllPlay percentage from the weekly scores, between 0 and 1."`
+	Record            RecordV1 `json:"record" description:"Head to head record from the weekly results."`
+}
+
+// powerRankings rates every franchise from the played games on the schedule alone, so the rankings
+// don't depend on MFL's power rankings page. franchises is in championship order.
+func powerRankings(franchises []Franchise, games []Game, config PowerRankingsConfig) []PowerRankingV1 {
+	scores := map[string][]float64{}
+	allPlay := map[string]RecordV1{}
+	records := map[string]RecordV1{}
+	for week := 1; week <= latestPlayedWeek(games); week++ {
+		weekGames := playedGamesInWeek(games, week)
+		for id, record := range weeklyAllPlay(weekGames) {
+			allPlay[id] = addRecords(allPlay[id], record)
+		}
+		for _, game := range weekGames {
+			scores[game.Home] = append(scores[game.Home], game.HomeScore)
+			scores[game.Away] = append(scores[game.Away], game.AwayScore)
+			switch {
+			case game.HomeScore > game.AwayScore:
+				records[game.Home] = addRecords(records[game.Home], RecordV1{Wins: 1})
+				records[game.Away] = addRecords(records[game.Away], RecordV1{Losses: 1})
+			case game.AwayScore > game.HomeScore:
+				records[game.Home] = addRecords(records[game.Home], RecordV1{Losses: 1})
+				records[game.Away] = addRecords(records[game.Away], RecordV1{Wins: 1})
+			default:
+				records[game.Home] = addRecords(records[game.Home], RecordV1{Ties: 1})
+				records[game.Away] = addRecords(records[game.Away], RecordV1{Ties: 1})
+			}
+		}
+	}
+
+	rankings := make([]PowerRankingV1, len(franchises))
+	recentForm := make([]float64, len(franchises))
+	pointsFor := make([]float64, len(franchises))
+	allPlayPct := make([]float64, len(franchises))
+	recordPct := make([]float64, len(franchises))
+	for i, franchise := range franchises {
+		id := franchise.TeamID
+		recent := scores[id]
+		if len(recent) > config.RecentWeeks {
+			recent = recent[len(recent)-config.RecentWeeks:]
+		}
+		recentForm[i] = meanScore(recent)
+		pointsFor[i] = meanScore(scores[id])
+		allPlayPct[i] = winPercentage(allPlay[id])
+		recordPct[i] = winPercentage(records[id])
+
+		rankings[i] = PowerRankingV1{
+			StandingsRank:     i + 1,
+			FranchiseID:       id,
+			DisplayName:       displayLabel(franchise),
+			RecentForm:        roundFloat(recentForm[i], 2),
+			PointsFor:         roundFloat(pointsFor[i], 2),
+			AllPlayPercentage: roundFloat(allPlayPct[i], 3),
+			Record:            records[id],
+		}
+	}
+
+	w := config.Weights
+	factors := []struct {
+		weight float64
+		values []float64
+	}{
+		{w.RecentForm, recentForm},
+		{w.PointsFor, pointsFor},
+		{w.AllPlay, allPlayPct},
+		{w.Record, recordPct},
+	}
+	totalWeight := w.RecentForm + w.PointsFor + w.AllPlay + w.Record
+	for _, factor := range factors {
+		for i, rating := range factorRatings(factor.values) {
+			rankings[i].PowerScore += 100 * factor.weight / totalWeight * rating
+		}
+	}
+
+	for i := range rankings {
+		rankings[i].PowerScore = roundFloat(rankings[i].PowerScore, 1)
+	}
+	sort.SliceStable(rankings, func(i, j int) bool {
+		return rankings[i].PowerScore > rankings[j].PowerScore
+	})
+	for i := range rankings {
+		rankings[i].Rank = i + 1
+	}
+
+	return rankings
+}
+
+// factorRatings scales values from 0 for the lowest to 1 for the highest. When every value is the
+// same nobody is ahead, so everyone gets the middle rating.
+func factorRatings(values []float64) []float64 {
+	ratings := make([]float64, len(values))
+	if len(values) == 0 {
+		return ratings
+	}
+
+	lowest, highest := values[0], values[0]
+	for _, value := range values {
+		lowest = min(lowest, value)
+		highest = max(highest, value)
+	}
+
+	for i, value := range values {
+		if highest == lowest {
+			ratings[i] = 0.5
+			continue
+		}
+		ratings[i] = (value - lowest) / (highest - lowest)
+	}
+
+	return ratings
+}
+
+func meanScore(scores []float64) float64 {
+	if len(scores) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, score := range scores {
+		sum += score
+	}
+
+	return sum / float64(len(scores))
+}
+
+// addRecords totals two records.
+func addRecords(a, b RecordV1) RecordV1 {
+	return RecordV1{Wins: a.Wins + b.Wins, Losses: a.Losses + b.Losses, Ties: a.Ties + b.Ties}
+}
+
+func printPowerRankingsTable(rankings []PowerRankingV1) string {
+	t := table.NewWriter()
+	t.SetOutputMirror(&bytes.Buffer{})
+	t.AppendHeader(table.Row{"Rank", "Team", "Power", "Form", "Avg Pts", "AllPlay %", "W-L-T", "Standing"})
+	for _, r := range rankings {
+		t.AppendRow(table.Row{r.Rank, r.DisplayName, formatScore(r.PowerScore), formatPoints(r.RecentForm),
+			formatPoints(r.PointsFor), strconv.FormatFloat(r.AllPlayPercentage, 'f', 3, 64),
+			formatRecord(r.Record.Wins, r.Record.Losses, r.Record.Ties), ordinal(r.StandingsRank)})
+	}
+
+	return t.Render()
+}
+
+func servePowerRankings(ctx context.Context, request events.APIGatewayProxyRequest,
+	_ map[string]string) (events.APIGatewayProxyResponse, error) {
+	format, err := jsonOrTextFormat(request)
+	if err != nil {
+		return textResponse(http.StatusNotAcceptable, err.Error()), nil
+	}
+
+	standings, policy, err := standingsForRoute(ctx, "/power-rankings", request)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	config, err := currentConfig()
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	games, err := fetchSchedule(ctx)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	rankings := powerRankings(applyDisplayPolicy(standings.Franchises, policy).Franchise, games,
+		config.PowerRankings)
+	if format == FormatJSON {
+		return jsonResponse(http.StatusOK, PowerRankingsResponseV1{
+			SchemaVersion: ResponseSchemaVersion,
+			Metadata:      newStandingsResponseV1(standings, policy, time.Now()).Metadata,
+			RecentWeeks:   config.PowerRankings.RecentWeeks,
+			Weights:       config.PowerRankings.Weights,
+			Franchises:    rankings,
+		}, "application/json")
+	}
+
+	return textResponse(http.StatusOK, printPowerRankingsTable(rankings)+formatWarnings(standings.Warnings)), nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Against recapGames A scored 100 then 70, B 90 then 95, C 80 then 110 and D 79.5 then 95.
+func TestPowerRankings(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   PowerRankingsConfig
+		order    []string
+		expected map[string]float64
+	}{
+		{name: "defaults", config: defaultPowerRankingsConfig(), order: []string{"C", "B", "A", "D"},
+			expected: map[string]float64{"A": 20, "B": 65, "C": 100, "D": 13.5}},
+		{name: "latest week only", config: PowerRankingsConfig{RecentWeeks: 1,
+			Weights: PowerRankingWeights{RecentForm: 2}}, order: []string{"C", "B", "D", "A"},
+			expected: map[string]float64{"A": 0, "B": 62.5, "C": 100, "D": 62.5}},
+		{name: "record", config: PowerRankingsConfig{RecentWeeks: 3, Weights: PowerRankingWeights{Record: 1}},
+			order: []string{"C", "A", "B", "D"}, expected: map[string]float64{"A": 33.3, "B": 0, "C": 100, "D": 0}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rankings := powerRankings(scenarioFranchises(), recapGames(), tc.config)
+
+			var order []string
+			scores := map[string]float64{}
+			for i, ranking := range rankings {
+				assert.Equal(t, i+1, ranking.Rank)
+				order = append(order, ranking.DisplayName)
+				scores[ranking.DisplayName] = ranking.PowerScore
+			}
+			assert.Equal(t, tc.order, order)
+			assert.Equal(t, tc.expected, scores)
+		})
+	}
+}
+
+func TestPowerRankingsFactors(t *testing.T) {
+	rankings := powerRankings(scenarioFranchises(), recapGames(), PowerRankingsConfig{RecentWeeks: 1,
+		Weights: PowerRankingWeights{PointsFor: 1}})
+
+	c := rankings[0]
+	assert.Equal(t, "C", c.DisplayName)
+	assert.Equal(t, 110.0, c.RecentForm)
+	assert.Equal(t, 95.0, c.PointsFor)
+	assert.Equal(t, 0.667, c.AllPlayPercentage)
+	assert.Equal(t, RecordV1{Wins: 2}, c.Record)
+
+	unplayed := powerRankings(scenarioFranchises(), nil, defaultPowerRankingsConfig())
+	for _, ranking := range unplayed {
+		assert.Equal(t, 50.0, ranking.PowerScore, "nobody is ahead before a game is played")
+		assert.Equal(t, ranking.StandingsRank, ranking.Rank)
+	}
+}
+
+func TestPowerRankingsConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config PowerRankingsConfig
+		errMsg string
+	}{
+		{name: "defaults", config: defaultPowerRankingsConfig()},
+		{name: "no recent weeks", config: PowerRankingsConfig{Weights: PowerRankingWeights{Record: 1}},
+			errMsg: "power_rankings recent_weeks must be at least 1"},
+		{name: "negative", config: PowerRankingsConfig{RecentWeeks: 1,
+			Weights: PowerRankingWeights{Record: 1, AllPlay: -1}}, errMsg: "power_rankings weights can't be negative"},
+		{name: "all zero", config: PowerRankingsConfig{RecentWeeks: 1},
+			errMsg: "power_rankings needs at least one weight above 0"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.validate()
+			if tc.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestPrintPowerRankingsTable(t *testing.T) {
+	table := printPowerRankingsTable(powerRankings(scenarioFranchises(), recapGames(), defaultPowerRankingsConfig()))
+
+	assert.Contains(t, table, "STANDING")
+	assert.Contains(t, table, "100.0")
+	assert.Contains(t, table, "2-0-0")
+}
+
+func TestServePowerRankingsRejectsUnsupportedFormatBeforeFetching(t *testing.T) {
+	response, err := servePowerRankings(context.Background(), events.APIGatewayProxyRequest{
+		QueryStringParameters: map[string]string{"output": "csv"},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotAcceptable, response.StatusCode)
+}
@@ -0,0 +1,416 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+const recapListLength int = 3
+
+var errNoRecapWeek = errors.New("no completed week to recap")
+
+type RecapResponseV1 struct {
+	SchemaVersion string             `json:"schema_version" description:"Version of this response schema."`
+	Metadata      ResponseMetadataV1 `json:"metadata" description:"Where the standings came from and how fresh they are."`
+	Recap         RecapV1            `json:"recap" description:"The week in review."`
+}
+
+// RecapV1 summarizes one scoring week. Championship standings are rebuilt from the schedule as of
+// the end of the week and the week before, so movers compare like with like.
+type RecapV1 struct {
+	Week         int               `json:"week" description:"Week being recapped."`
+	Leader       RecapMoverV1      `json:"leader" description:"Championship leader after the week."`
+	Risers       []RecapMoverV1    `json:"risers" description:"Biggest championship point gains this week."`
+	Fallers      []RecapMoverV1    `json:"fallers" description:"Biggest championship point losses this week."`
+	HighScore    RecapTeamWeekV1   `json:"high_score" description:"Highest score of the week."`
+	LowScore     RecapTeamWeekV1   `json:"low_score" description:"Lowest score of the week."`
+	ClosestGames []RecapGameV1     `json:"closest_games" description:"Tightest head to head games of the week."`
+	Teams        []RecapTeamWeekV1 `json:"teams" description:"Every franchise's week, highest score first."`
+}
+
+type RecapMoverV1 struct {
+	FranchiseID string  `json:"franchise_id" description:"MFL franchise ID, e.g. 0003."`
+	DisplayName string  `json:"display_name" description:"Label to show for the franchise under the display policy."`
+	Rank        int     `json:"rank" description:"Championship position after the week."`
+	RankChange  int     `json:"rank_change" description:"Places gained over the week, negative when places were lost."`
+	TotalScore  float64 `json:"total_score" description:"Championship points after the week."`
+	Change      float64 `json:"change" description:"Championship points gained or lost over the week."`
+}
+
+type RecapTeamWeekV1 struct {
+	FranchiseID string   `json:"franchise_id" description:"MFL franchise ID, e.g. 0003."`
+	DisplayName string   `json:"display_name" description:"Label to show for the franchise under the display policy."`
+	Score       float64  `json:"score" description:"Fantasy points scored this week."`
+	Result      string   `json:"result" description:"Head to head result: W, L or T."`
+	AllPlay     RecordV1 `json:"all_play" description:"Record this week against every other franchise's score."`
+}
+
+type RecapGameV1 struct {
+	Winner      string  `json:"winner" description:"Display name of the winner, or the home franchise after a tie."`
+	Loser       string  `json:"loser" description:"Display name of the loser, or the away franchise after a tie."`
+	WinnerScore float64 `json:"winner_score" description:"Winner's score."`
+	LoserScore  float64 `json:"loser_score" description:"Loser's score."`
+	Margin      float64 `json:"margin" description:"Points between the two scores."`
+}
+
+// latestPlayedWeek is the most recent week with a result.
+func latestPlayedWeek(games []Game) int {
+	week := 0
+	for _, game := range games {
+		if game.Played && game.Week > week {
+			week = game.Week
+		}
+	}
+
+	return week
+}
+
+// standingsThroughWeek replays the schedule up to and including week and scores the result. AllPlay
+// and median records are rebuilt from the weekly scores too, so they match the week as well.
+func standingsThroughWeek(franchises []Franchise, games []Game, scoring ScoringConfig, week int) Franchises {
+	replayed := make([]Franchise, len(franchises))
+	index := map[string]int{}
+	for i, franchise := range franchises {
+		replayed[i] = Franchise{TeamID: franchise.TeamID, TeamName: franchise.TeamName,
+			OwnerName: franchise.OwnerName, OwnerID: franchise.OwnerID, OwnerAlias: franchise.OwnerAlias,
+			Division: franchise.Division, DisplayName: franchise.DisplayName}
+		index[franchise.TeamID] = i
+	}
+
+	for w := 1; w <= week; w++ {
+		weekGames := playedGamesInWeek(games, w)
+		for _, game := range weekGames {
+			home, homeOK := index[game.Home]
+			away, awayOK := index[game.Away]
+			if homeOK && awayOK {
+				playGame(&replayed[home], &replayed[away], game.HomeScore, game.AwayScore)
+			}
+		}
+		if scoring.scoresMedian() {
+			playMedianWeek(replayed, index, weekGames)
+		}
+
+		for id, record := range weeklyAllPlay(weekGames) {
+			if i, ok := index[id]; ok {
+				replayed[i].AllPlayWins += record.Wins
+				replayed[i].AllPlayLosses += record.Losses
+				replayed[i].AllPlayTies += record.Ties
+			}
+		}
+	}
+
+	for i := range replayed {
+		f := &replayed[i]
+		if games := f.AllPlayWins + f.AllPlayLosses + f.AllPlayTies; games > 0 {
+			f.AllPlayPercentage = (float64(f.AllPlayWins) + float64(f.AllPlayTies)/2) / float64(games)
+		}
+	}
+
+	if scoring.scoresMedian() {
+		populateMedianRecords(Franchises{Franchise: replayed})
+	}
+
+	return rankFranchises(scoreChampionship(Franchises{Franchise: replayed}, scoring), scoring)
+}
+
+func playedGamesInWeek(games []Game, week int) []Game {
+	var played []Game
+	for _, game := range games {
+		if game.Week == week && game.Played {
+			played = append(played, game)
+		}
+	}
+
+	return played
+}
+
+// weeklyAllPlay compares every score of the week against every other.
+func weeklyAllPlay(games []Game) map[string]RecordV1 {
+	scores := map[string]float64{}
+	for _, game := range games {
+		scores[game.Home] = game.HomeScore
+		scores[game.Away] = game.AwayScore
+	}
+
+	records := map[string]RecordV1{}
+	for id, score := range scores {
+		var record RecordV1
+		for otherID, other := range scores {
+			switch {
+			case otherID == id:
+			case score > other:
+				record.Wins++
+			case score < other:
+				record.Losses++
+			default:
+				record.Ties++
+			}
+		}
+		records[id] = record
+	}
+
+	return records
+}
+
+// buildRecap summarizes week from the policy-redacted franchises and the schedule.
+func buildRecap(franchises []Franchise, games []Game, scoring ScoringConfig, week int) (RecapV1, error) {
+	weekGames := playedGamesInWeek(games, week)
+	if len(weekGames) == 0 || len(franchises) == 0 {
+		return RecapV1{}, fmt.Errorf("%w: week %d has no results", errNoRecapWeek, week)
+	}
+
+	names := map[string]string{}
+	for _, franchise := range franchises {
+		names[franchise.TeamID] = displayLabel(franchise)
+	}
+	name := func(id string) string {
+		if label, ok := names[id]; ok {
+			return label
+		}
+		return id
+	}
+
+	recap := RecapV1{Week: week}
+
+	before := map[string]Franchise{}
+	beforeRank := map[string]int{}
+	for i, franchise := range standingsThroughWeek(franchises, games, scoring, week-1).Franchise {
+		before[franchise.TeamID] = franchise
+		beforeRank[franchise.TeamID] = i + 1
+	}
+
+	var movers []RecapMoverV1
+	for i, franchise := range standingsThroughWeek(franchises, games, scoring, week).Franchise {
+		movers = append(movers, RecapMoverV1{
+			FranchiseID: franchise.TeamID,
+			DisplayName: name(franchise.TeamID),
+			Rank:        i + 1,
+			RankChange:  beforeRank[franchise.TeamID] - (i + 1),
+			TotalScore:  franchise.TotalScore,
+			Change:      roundFloat(franchise.TotalScore-before[franchise.TeamID].TotalScore, 2),
+		})
+	}
+	recap.Leader = movers[0]
+
+	byChange := append([]RecapMoverV1{}, movers...)
+	sort.SliceStable(byChange, func(i, j int) bool { return byChange[i].Change > byChange[j].Change })
+	for _, mover := range byChange {
+		if mover.Change > 0 && len(recap.Risers) < recapListLength {
+			recap.Risers = append(recap.Risers, mover)
+		}
+	}
+	for i := len(byChange) - 1; i >= 0; i-- {
+		if byChange[i].Change < 0 && len(recap.Fallers) < recapListLength {
+			recap.Fallers = append(recap.Fallers, byChange[i])
+		}
+	}
+
+	allPlay := weeklyAllPlay(weekGames)
+	for _, game := range weekGames {
+		homeResult, awayResult := "T", "T"
+		switch {
+		case game.HomeScore > game.AwayScore:
+			homeResult, awayResult = "W", "L"
+		case game.AwayScore > game.HomeScore:
+			homeResult, awayResult = "L", "W"
+		}
+		recap.Teams = append(recap.Teams,
+			RecapTeamWeekV1{FranchiseID: game.Home, DisplayName: name(game.Home), Score: game.HomeScore,
+				Result: homeResult, AllPlay: allPlay[game.Home]},
+			RecapTeamWeekV1{FranchiseID: game.Away, DisplayName: name(game.Away), Score: game.AwayScore,
+				Result: awayResult, AllPlay: allPlay[game.Away]})
+
+		winner, loser, winnerScore, loserScore := game.Home, game.Away, game.HomeScore, game.AwayScore
+		if game.AwayScore > game.HomeScore {
+			winner, loser, winnerScore, loserScore = game.Away, game.Home, game.AwayScore, game.HomeScore
+		}
+		recap.ClosestGames = append(recap.ClosestGames, RecapGameV1{Winner: name(winner), Loser: name(loser),
+			WinnerScore: winnerScore, LoserScore: loserScore, Margin: roundFloat(winnerScore-loserScore, 2)})
+	}
+
+	sort.SliceStable(recap.Teams, func(i, j int) bool { return recap.Teams[i].Score > recap.Teams[j].Score })
+	recap.HighScore = recap.Teams[0]
+	recap.LowScore = recap.Teams[len(recap.Teams)-1]
+
+	sort.SliceStable(recap.ClosestGames, func(i, j int) bool {
+		return recap.ClosestGames[i].Margin < recap.ClosestGames[j].Margin
+	})
+	recap.ClosestGames = recap.ClosestGames[:min(recapListLength, len(recap.ClosestGames))]
+
+	return recap, nil
+}
+
+// renderRecapMarkdown is meant to be pasted into the league message board.
+func renderRecapMarkdown(recap RecapV1) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Week %d Recap\n\n", recap.Week)
+	fmt.Fprintf(&b, "**Leader:** %s with %s championship points\n\n", recap.Leader.DisplayName,
+		formatScore(recap.Leader.TotalScore))
+
+	b.WriteString("## Movers\n\n")
+	for _, line := range moverLines(recap) {
+		b.WriteString("- " + line + "\n")
+	}
+
+	b.WriteString("\n## Scores\n\n")
+	fmt.Fprintf(&b, "- High: %s, %s\n", recap.HighScore.DisplayName, formatPoints(recap.HighScore.Score))
+	fmt.Fprintf(&b, "- Low: %s, %s\n", recap.LowScore.DisplayName, formatPoints(recap.LowScore.Score))
+
+	b.WriteString("\n## Closest Games\n\n")
+	for _, game := range recap.ClosestGames {
+		b.WriteString("- " + gameLine(game) + "\n")
+	}
+
+	b.WriteString("\n## AllPlay\n\n")
+	b.WriteString(recapTeamTable(recap).RenderMarkdown())
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+func renderRecapText(recap RecapV1) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "WEEK %d RECAP\n\n", recap.Week)
+	fmt.Fprintf(&b, "Leader: %s with %s championship points\n\n", recap.Leader.DisplayName,
+		formatScore(recap.Leader.TotalScore))
+
+	b.WriteString("Movers:\n")
+	for _, line := range moverLines(recap) {
+		b.WriteString("  " + line + "\n")
+	}
+
+	fmt.Fprintf(&b, "\nHigh score: %s, %s\n", recap.HighScore.DisplayName, formatPoints(recap.HighScore.Score))
+	fmt.Fprintf(&b, "Low score: %s, %s\n", recap.LowScore.DisplayName, formatPoints(recap.LowScore.Score))
+
+	b.WriteString("\nClosest games:\n")
+	for _, game := range recap.ClosestGames {
+		b.WriteString("  " + gameLine(game) + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(recapTeamTable(recap).Render())
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+func moverLines(recap RecapV1) []string {
+	var lines []string
+	for _, mover := range append(append([]RecapMoverV1{}, recap.Risers...), recap.Fallers...) {
+		lines = append(lines, fmt.Sprintf("%s %s to %s (%s)", mover.DisplayName, formatChange(mover.Change),
+			formatScore(mover.TotalScore), describeRankChange(mover)))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "No change in championship points.")
+	}
+
+	return lines
+}
+
+func describeRankChange(mover RecapMoverV1) string {
+	switch {
+	case mover.RankChange > 0:
+		return fmt.Sprintf("up %d to %s", mover.RankChange, ordinal(mover.Rank))
+	case mover.RankChange < 0:
+		return fmt.Sprintf("down %d to %s", -mover.RankChange, ordinal(mover.Rank))
+	default:
+		return "still " + ordinal(mover.Rank)
+	}
+}
+
+func gameLine(game RecapGameV1) string {
+	if game.Margin == 0 {
+		return fmt.Sprintf("%s and %s tied at %s", game.Winner, game.Loser, formatPoints(game.WinnerScore))
+	}
+
+	return fmt.Sprintf("%s beat %s %s-%s (by %s)", game.Winner, game.Loser, formatPoints(game.WinnerScore),
+		formatPoints(game.LoserScore), formatPoints(game.Margin))
+}
+
+func formatPoints(points float64) string {
+	return strconv.FormatFloat(points, 'f', 2, 64)
+}
+
+func recapTeamTable(recap RecapV1) table.Writer {
+	t := table.NewWriter()
+	t.SetOutputMirror(&bytes.Buffer{})
+	t.AppendHeader(table.Row{"Team", "Score", "Result", AllPlayRecord})
+	for _, team := range recap.Teams {
+		t.AppendRow(table.Row{team.DisplayName, formatPoints(team.Score), team.Result,
+			formatRecord(team.AllPlay.Wins, team.AllPlay.Losses, team.AllPlay.Ties)})
+	}
+
+	return t
+}
+
+// recapWeek reads ?week=, defaulting to the latest week with results.
+func recapWeek(query map[string]string, games []Game) (int, error) {
+	value, ok := query["week"]
+	if !ok {
+		if week := latestPlayedWeek(games); week > 0 {
+			return week, nil
+		}
+		return 0, errNoRecapWeek
+	}
+
+	week, err := strconv.Atoi(value)
+	if err != nil || week < 1 {
+		return 0, errors.New("week must be a positive whole number")
+	}
+
+	return week, nil
+}
+
+func serveRecap(ctx context.Context, request events.APIGatewayProxyRequest,
+	_ map[string]string) (events.APIGatewayProxyResponse, error) {
+	format, err := restrictedFormat(request, FormatMarkdown, FormatText, FormatJSON)
+	if err != nil {
+		return textResponse(http.StatusNotAcceptable, err.Error()), nil
+	}
+
+	standings, policy, err := standingsForRoute(ctx, "/recap", request)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	games, err := fetchSchedule(ctx)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	week, err := recapWeek(request.QueryStringParameters, games)
+	if err != nil {
+		return textResponse(http.StatusBadRequest, err.Error()), nil
+	}
+
+	recap, err := buildRecap(applyDisplayPolicy(standings.Franchises, policy).Franchise, games, standings.Scoring,
+		week)
+	if err != nil {
+		return textResponse(http.StatusNotFound, err.Error()), nil
+	}
+
+	switch format {
+	case FormatJSON:
+		return jsonResponse(http.StatusOK, RecapResponseV1{
+			SchemaVersion: ResponseSchemaVersion,
+			Metadata:      newStandingsResponseV1(standings, policy, time.Now()).Metadata,
+			Recap:         recap,
+		}, "application/json")
+	case FormatText:
+		return textResponse(http.StatusOK, renderRecapText(recap)), nil
+	default:
+		response := textResponse(http.StatusOK, renderRecapMarkdown(recap))
+		response.Headers["content-type"] = renderers()[FormatMarkdown].contentType
+		return response, nil
+	}
+}
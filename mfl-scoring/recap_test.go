@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recapGames is two weeks for scenarioFranchises. After week 1 the table is A 7.5, C 5.5, B 4.5,
+// D 2.5; after week 2 it is C 8.0, B 4.5, A 4.0, D 3.5.
+func recapGames() []Game {
+	return []Game{
+		{Week: 1, Home: "0001", Away: "0002", HomeScore: 100, AwayScore: 90, Played: true},
+		{Week: 1, Home: "0003", Away: "0004", HomeScore: 80, AwayScore: 79.5, Played: true},
+		{Week: 2, Home: "0001", Away: "0003", HomeScore: 70, AwayScore: 110, Played: true},
+		{Week: 2, Home: "0002", Away: "0004", HomeScore: 95, AwayScore: 95, Played: true},
+		{Week: 3, Home: "0001", Away: "0004"},
+		{Week: 3, Home: "0002", Away: "0003"},
+	}
+}
+
+func TestStandingsThroughWeek(t *testing.T) {
+	standings := standingsThroughWeek(scenarioFranchises(), recapGames(), ScoringConfig{}, 1).Franchise
+
+	var order []string
+	var totals []float64
+	for _, franchise := range standings {
+		order = append(order, franchise.TeamName)
+		totals = append(totals, franchise.TotalScore)
+	}
+	assert.Equal(t, []string{"A", "C", "B", "D"}, order)
+	assert.Equal(t, []float64{7.5, 5.5, 4.5, 2.5}, totals)
+	assert.Equal(t, 1.0, standings[0].AllPlayPercentage)
+	assert.Equal(t, 3, standings[0].AllPlayWins)
+
+	assert.Zero(t, standingsThroughWeek(scenarioFranchises(), recapGames(), ScoringConfig{}, 0).Franchise[0].PointsFor)
+}
+
+func TestBuildRecap(t *testing.T) {
+	recap, err := buildRecap(scenarioFranchises(), recapGames(), ScoringConfig{}, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, recap.Week)
+	assert.Equal(t, "C", recap.Leader.DisplayName)
+	assert.Equal(t, 8.0, recap.Leader.TotalScore)
+
+	require.Len(t, recap.Risers, 2)
+	assert.Equal(t, RecapMoverV1{FranchiseID: "0003", DisplayName: "C", Rank: 1, RankChange: 1, TotalScore: 8,
+		Change: 2.5}, recap.Risers[0])
+	assert.Equal(t, "D", recap.Risers[1].DisplayName)
+	require.Len(t, recap.Fallers, 1)
+	assert.Equal(t, RecapMoverV1{FranchiseID: "0001", DisplayName: "A", Rank: 3, RankChange: -2, TotalScore: 4,
+		Change: -3.5}, recap.Fallers[0])
+
+	assert.Equal(t, "C", recap.HighScore.DisplayName)
+	assert.Equal(t, RecordV1{Wins: 3}, recap.HighScore.AllPlay)
+	assert.Equal(t, "A", recap.LowScore.DisplayName)
+	assert.Equal(t, "L", recap.LowScore.Result)
+
+	require.Len(t, recap.ClosestGames, 2)
+	assert.Equal(t, 0.0, recap.ClosestGames[0].Margin)
+	assert.Equal(t, 40.0, recap.ClosestGames[1].Margin)
+	assert.Equal(t, "C", recap.ClosestGames[1].Winner)
+
+	assert.Len(t, recap.Teams, 4)
+	assert.Equal(t, RecordV1{Wins: 1, Losses: 1, Ties: 1}, recap.Teams[1].AllPlay)
+}
+
+func TestBuildRecapWithoutResults(t *testing.T) {
+	_, err := buildRecap(scenarioFranchises(), recapGames(), ScoringConfig{}, 3)
+	assert.ErrorIs(t, err, errNoRecapWeek)
+}
+
+func TestRenderRecap(t *testing.T) {
+	recap, err := buildRecap(scenarioFranchises(), recapGames(), ScoringConfig{}, 2)
+	require.NoError(t, err)
+
+	markdown := renderRecapMarkdown(recap)
+	assert.Contains(t, markdown, "# Week 2 Recap")
+	assert.Contains(t, markdown, "**Leader:** C with 8.0 championship points")
+	assert.Contains(t, markdown, "- C +2.5 to 8.0 (up 1 to 1st)")
+	assert.Contains(t, markdown, "- A -3.5 to 4.0 (down 2 to 3rd)")
+	assert.Contains(t, markdown, "- B and D tied at 95.00")
+	assert.Contains(t, markdown, "- C beat A 110.00-70.00 (by 40.00)")
+	assert.Contains(t, markdown, "| C | 110.00 | W | 3-0-0 |")
+
+	text := renderRecapText(recap)
+	assert.Contains(t, text, "WEEK 2 RECAP")
+	assert.Contains(t, text, "High score: C, 110.00")
+	assert.NotContains(t, text, "#")
+}
+
+func TestRecapWeek(t *testing.T) {
+	week, err := recapWeek(map[string]string{}, recapGames())
+	require.NoError(t, err)
+	assert.Equal(t, 2, week)
+
+	week, err = recapWeek(map[string]string{"week": "1"}, recapGames())
+	require.NoError(t, err)
+	assert.Equal(t, 1, week)
+
+	_, err = recapWeek(map[string]string{"week": "zero"}, recapGames())
+	assert.Error(t, err)
+	_, err = recapWeek(map[string]string{}, []Game{{Week: 1}})
+	assert.ErrorIs(t, err, errNoRecapWeek)
+}
+
+func TestServeRecapRejectsUnsupportedFormatBeforeFetching(t *testing.T) {
+	response, err := serveRecap(context.Background(), events.APIGatewayProxyRequest{
+		QueryStringParameters: map[string]string{"output": "csv"},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotAcceptable, response.StatusCode)
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+type OutputFormat string
+
+const (
+	FormatText     OutputFormat = "text"
+	FormatJSON     OutputFormat = "json"
+	FormatCSV      OutputFormat = "csv"
+	FormatMarkdown OutputFormat = "markdown"
+	FormatHTML     OutputFormat = "html"
+	FormatTSV      OutputFormat = "tsv"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported output format")
+
+// renderer turns scored standings into one output format. Every renderer applies the display
+// policy itself, so adding a format can't bypass it.
+type renderer struct {
+	contentType string
+	render      func(standings Standings, policy DisplayPolicy, now time.Time) (string, error)
+}
+
+// renderers is the registry of output formats, keyed by the ?output= value.
+func renderers() map[OutputFormat]renderer {
+	return map[OutputFormat]renderer{
+		FormatText:     {contentType: "text/plain; charset=utf-8", render: renderText},
+		FormatJSON:     {contentType: "application/json", render: renderJSON},
+		FormatCSV:      {contentType: "text/csv; charset=utf-8", render: renderCSV},
+		FormatMarkdown: {contentType: "text/markdown; charset=utf-8", render: renderMarkdown},
+		FormatHTML:     {contentType: "text/html; charset=utf-8", render: renderPage},
+		FormatTSV:      {contentType: "text/tab-separated-values; charset=utf-8", render: renderTSV},
+	}
+}
+
+// formatAliases maps the shorter ?output= spellings people reach for onto a format.
+var formatAliases = map[string]OutputFormat{
+	"txt": FormatText,
+	"md":  FormatMarkdown,
+}
+
+// mediaTypeFormats maps Accept header media types onto a format.
+var mediaTypeFormats = map[string]OutputFormat{
+	"text/plain":                FormatText,
+	"application/json":          FormatJSON,
+	"text/csv":                  FormatCSV,
+	"text/markdown":             FormatMarkdown,
+	"text/html":                 FormatHTML,
+	"text/tab-separated-values": FormatTSV,
+}
+
+// negotiateFormat picks the output format. An explicit ?output= always wins and must name a known
+// format; otherwise the Accept header is honored by quality, and anything unrecognized falls back
+// to the text table.
+func negotiateFormat(output, accept string) (OutputFormat, error) {
+	registry := renderers()
+
+	if output != "" {
+		format := OutputFormat(strings.ToLower(output))
+		if alias, ok := formatAliases[string(format)]; ok {
+			format = alias
+		}
+		if _, ok := registry[format]; !ok {
+			return "", fmt.Errorf("%w %q, expected one of %s", ErrUnsupportedFormat, output,
+				strings.Join(supportedFormats(), ", "))
+		}
+		return format, nil
+	}
+
+	if format, ok := acceptedFormat(accept); ok {
+		return format, nil
+	}
+
+	return FormatText, nil
+}
+
+// acceptedFormat is the registered format the Accept header prefers, if it names any.
+func acceptedFormat(accept string) (OutputFormat, bool) {
+	type candidate struct {
+		format  OutputFormat
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if format, ok := mediaTypeFormats[mediaType]; ok && quality > 0 {
+			candidates = append(candidates, candidate{format: format, quality: quality})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	return candidates[0].format, true
+}
+
+// restrictedFormat negotiates for endpoints that only come in some formats; the first allowed
+// format is the default. Anything else asked for via Accept falls back to the default, while an
+// explicit ?output= for another format is refused.
+func restrictedFormat(request events.APIGatewayProxyRequest, allowed ...OutputFormat) (OutputFormat, error) {
+	output := request.QueryStringParameters["output"]
+	accept := headerValue(request.Headers, "Accept")
+	if _, ok := acceptedFormat(accept); output == "" && !ok {
+		return allowed[0], nil
+	}
+
+	format, err := negotiateFormat(output, accept)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, len(allowed))
+	for i, candidate := range allowed {
+		if format == candidate {
+			return format, nil
+		}
+		names[i] = string(candidate)
+	}
+
+	if output != "" {
+		return "", fmt.Errorf("%w %q here, expected one of %s", ErrUnsupportedFormat, output,
+			strings.Join(names, ", "))
+	}
+
+	return allowed[0], nil
+}
+
+// jsonOrTextFormat is for endpoints that answer with a plain text narrative or a JSON document.
+func jsonOrTextFormat(request events.APIGatewayProxyRequest) (OutputFormat, error) {
+	return restrictedFormat(request, FormatText, FormatJSON)
+}
+
+func supportedFormats() []string {
+	formats := make([]string, 0, len(renderers()))
+	for format := range renderers() {
+		formats = append(formats, string(format))
+	}
+	sort.Strings(formats)
+
+	return formats
+}
+
+// renderStandings builds the response for a negotiated format.
+func renderStandings(format OutputFormat, standings Standings, policy DisplayPolicy,
+	now time.Time) (events.APIGatewayProxyResponse, error) {
+	r, ok := renderers()[format]
+	if !ok {
+		return events.APIGatewayProxyResponse{}, fmt.Errorf("%w %q", ErrUnsupportedFormat, format)
+	}
+
+	body, err := r.render(standings, policy, now)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	headers := map[string]string{
+		"content-type":                     r.contentType,
+		"Access-Control-Allow-Origin":      "*",
+		"Access-Control-Allow-Credentials": "true",
+		"Vary":                             "Accept",
+	}
+	if format == FormatJSON {
+		headers["X-Schema-Version"] = ResponseSchemaVersion
+	}
+
+	return events.APIGatewayProxyResponse{
+		Headers:    headers,
+		Body:       body,
+		StatusCode: http.StatusOK,
+	}, nil
+}
+
+func renderText(standings Standings, policy DisplayPolicy, _ time.Time) (string, error) {
+	return printScoringTable(standings.Franchises, policy) + formatWarnings(standings.Warnings), nil
+}
+
+func renderJSON(standings Standings, policy DisplayPolicy, now time.Time) (string, error) {
+	body, err := json.Marshal(newStandingsResponseV1(standings, policy, now))
+	if err != nil {
+		return "", err
+	}
+
+	return string(body), nil
+}
+
+// CSV and TSV carry only the table so spreadsheets can import them as is; warnings are dropped.
+func renderCSV(standings Standings, policy DisplayPolicy, _ time.Time) (string, error) {
+	return policyTableWriter(standings.Franchises, policy).RenderCSV(), nil
+}
+
+func renderTSV(standings Standings, policy DisplayPolicy, _ time.Time) (string, error) {
+	return policyTableWriter(standings.Franchises, policy).RenderTSV(), nil
+}
+
+func renderMarkdown(standings Standings, policy DisplayPolicy, _ time.Time) (string, error) {
+	var b strings.Builder
+	b.WriteString(policyTableWriter(standings.Franchises, policy).RenderMarkdown())
+	for _, warning := range standings.Warnings {
+		b.WriteString("\n\n> **Warning:** " + warning)
+	}
+
+	return b.String(), nil
+}
+
+// policyTableWriter is the standings table with the display policy applied, ready for any of the
+// go-pretty render targets.
+func policyTableWriter(franchises Franchises, policy DisplayPolicy) table.Writer {
+	redacted := applyDisplayPolicy(franchises, policy)
+	if !policy.HidesTeamNames() {
+		return scoringTableWriter(redacted, "")
+	}
+
+	return scoringTableWriter(redacted, displayLabelHeader(policy.Mode))
+}
+
+// textResponse is a plain text response for errors the caller can fix, such as a bad ?output=.
+func textResponse(statusCode int, body string) events.APIGatewayProxyResponse {
+	return events.APIGatewayProxyResponse{
+		Headers:    map[string]string{"content-type": "text/plain; charset=utf-8"},
+		Body:       body,
+		StatusCode: statusCode,
+	}
+}
+
+// headerValue looks a header up case-insensitively, since API Gateway passes names through as sent.
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	testCases := []struct {
+		name     string
+		output   string
+		accept   string
+		expected OutputFormat
+		err      error
+	}{
+		{name: "default", expected: FormatText},
+		{name: "query", output: "csv", expected: FormatCSV},
+		{name: "query case", output: "JSON", expected: FormatJSON},
+		{name: "query alias", output: "md", expected: FormatMarkdown},
+		{name: "query beats accept", output: "tsv", accept: "application/json", expected: FormatTSV},
+		{name: "unknown query", output: "xml", err: ErrUnsupportedFormat},
+		{name: "accept", accept: "text/csv", expected: FormatCSV},
+		{name: "accept quality", accept: "text/html;q=0.5, application/json", expected: FormatJSON},
+		{name: "accept order breaks ties", accept: "text/markdown, text/html", expected: FormatMarkdown},
+		{name: "accept browser", accept: "text/html,application/xhtml+xml,*/*;q=0.8", expected: FormatHTML},
+		{name: "accept refused", accept: "application/json;q=0", expected: FormatText},
+		{name: "accept unknown", accept: "application/xml", expected: FormatText},
+		{name: "accept wildcard", accept: "*/*", expected: FormatText},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			format, err := negotiateFormat(tc.output, tc.accept)
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, format)
+		})
+	}
+}
+
+func TestRenderStandingsContentTypes(t *testing.T) {
+	testCases := map[OutputFormat]struct {
+		contentType string
+		contains    string
+	}{
+		FormatText:     {contentType: "text/plain; charset=utf-8", contains: "Warning: AllPlay data unavailable"},
+		FormatJSON:     {contentType: "application/json", contains: `"schema_version":"v1"`},
+		FormatCSV:      {contentType: "text/csv; charset=utf-8", contains: "Team 1,Owner 1,"},
+		FormatMarkdown: {contentType: "text/markdown; charset=utf-8", contains: "| Team 1 | Owner 1 |"},
+		FormatHTML:     {contentType: "text/html; charset=utf-8", contains: "<table"},
+		FormatTSV:      {contentType: "text/tab-separated-values; charset=utf-8", contains: "Team 1\tOwner 1\t"},
+	}
+	assert.Len(t, testCases, len(renderers()))
+
+	for format, tc := range testCases {
+		t.Run(string(format), func(t *testing.T) {
+			response, err := renderStandings(format, testStandings(), DisplayPolicy{Mode: DisplayFull}, time.Now())
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			assert.Equal(t, tc.contentType, response.Headers["content-type"])
+			assert.Equal(t, "Accept", response.Headers["Vary"])
+			assert.Contains(t, response.Body, tc.contains)
+		})
+	}
+}
+
+func TestJSONOrTextFormat(t *testing.T) {
+	testCases := []struct {
+		name     string
+		request  events.APIGatewayProxyRequest
+		expected OutputFormat
+		err      bool
+	}{
+		{name: "default", expected: FormatText},
+		{name: "json", request: events.APIGatewayProxyRequest{QueryStringParameters: map[string]string{"output": "json"}},
+			expected: FormatJSON},
+		{name: "browser", request: events.APIGatewayProxyRequest{Headers: map[string]string{"Accept": "text/html"}},
+			expected: FormatText},
+		{name: "explicit csv", request: events.APIGatewayProxyRequest{QueryStringParameters: map[string]string{"output": "csv"}},
+			err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			format, err := jsonOrTextFormat(tc.request)
+			if tc.err {
+				assert.ErrorIs(t, err, ErrUnsupportedFormat)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, format)
+		})
+	}
+}
+
+func TestRestrictedFormat(t *testing.T) {
+	request := events.APIGatewayProxyRequest{Headers: map[string]string{"Accept": "text/markdown"}}
+	format, err := restrictedFormat(request, FormatMarkdown, FormatText)
+	assert.NoError(t, err)
+	assert.Equal(t, FormatMarkdown, format)
+
+	format, err = restrictedFormat(events.APIGatewayProxyRequest{}, FormatMarkdown, FormatText)
+	assert.NoError(t, err)
+	assert.Equal(t, FormatMarkdown, format, "the first allowed format is the default")
+}
+
+func TestHeaderValue(t *testing.T) {
+	headers := map[string]string{"accept": "text/csv"}
+	assert.Equal(t, "text/csv", headerValue(headers, "Accept"))
+	assert.Equal(t, "", headerValue(headers, "Content-Type"))
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"time"
+)
+
+const ResponseSchemaVersion string = "v1"
+
+// StandingsResponseV1 is the public JSON contract for ?output=json. Fields are only ever added to
+// v1; renaming or removing one means introducing v2.
+type StandingsResponseV1 struct {
+	SchemaVersion string                `json:"schema_version" description:"Version of this response schema."`
+	Metadata      ResponseMetadataV1    `json:"metadata" description:"Where the standings came from and how fresh they are."`
+	Franchises    []FranchiseStandingV1 `json:"franchises" description:"Franchises in championship order, tiebreakers applied."`
+}
+
+type ResponseMetadataV1 struct {
+	LeagueID     string    `json:"league_id" description:"MyFantasyLeague league ID."`
+	LeagueName   string    `json:"league_name,omitempty" description:"League name as reported by MFL."`
+	Year         int       `json:"year" description:"Season the standings belong to."`
+	Week         int       `json:"week" description:"Most games any franchise has played, i.e. the last completed week. Counted from AllPlay results in points-only leagues."`
+	GeneratedAt  time.Time `json:"generated_at" description:"When this response was built."`
+	DataAsOf     time.Time `json:"data_as_of" description:"When the standings were fetched from MFL."`
+	Warnings     []string  `json:"warnings" description:"Problems that affected this response, such as missing AllPlay data."`
+	DisplayMode  string    `json:"display_mode" description:"Which names this response may show: full, owner_first_name, team_id or alias."`
+	LeagueFormat string    `json:"league_format,omitempty" description:"head_to_head, or points_only when records don't score."`
+	RecordMetric string    `json:"record_metric,omitempty" description:"How records are ranked when it isn't wins plus half a win per tie: win_percentage, ties_as_loss or games_back."`
+}
+
+type FranchiseStandingV1 struct {
+	Rank        int        `json:"rank" description:"Championship position, 1 is first."`
+	FranchiseID string     `json:"franchise_id" description:"MFL franchise ID, e.g. 0003."`
+	DisplayName string     `json:"display_name" description:"Label to show for the franchise under the display policy."`
+	TeamName    string     `json:"team_name,omitempty" description:"Franchise name. Omitted unless the display policy is full."`
+	OwnerName   string     `json:"owner_name,omitempty" description:"Owner name, or first name only. Omitted when the display policy hides owners."`
+	OwnerID     string     `json:"owner_id,omitempty" description:"Stable owner identity from the owner registry. Omitted when the display policy hides owners or the registry doesn't cover the franchise."`
+	OwnerAlias  string     `json:"owner_alias,omitempty" description:"Owner alias from the owner registry. Omitted when the display policy hides owners or there isn't one."`
+	Record      RecordV1   `json:"record" description:"Head to head record."`
+	PointsFor   float64    `json:"points_for" description:"Total fantasy points scored."`
+	PointsScore float64    `json:"points_score" description:"Championship points awarded for fantasy points."`
+	RecordScore float64    `json:"record_score" description:"Championship points awarded for head to head record."`
+	TotalScore  float64    `json:"total_score" description:"Sum of all championship point components."`
+	AllPlay     *AllPlayV1 `json:"all_play,omitempty" description:"AllPlay record. Omitted when it could not be scraped."`
+	Luck        *float64   `json:"luck,omitempty" description:"Head to head wins less the wins the AllPlay percentage expects. Omitted without AllPlay data."`
+
+	MedianRecord *RecordV1 `json:"median_record,omitempty" description:"Record against each week's median score. Omitted unless the league scores it."`
+
+	PointsAgainst        *float64 `json:"points_against,omitempty" description:"Total fantasy points scored against the franchise. Omitted when MFL doesn't report it."`
+	PotentialPoints      *float64 `json:"potential_points,omitempty" description:"Fantasy points the optimal lineup would have scored. Omitted when MFL doesn't report it."`
+	LineupEfficiency     *float64 `json:"lineup_efficiency,omitempty" description:"Share of potential points actually scored, between 0 and 1. Omitted without potential points."`
+	PointsAgainstScore   float64  `json:"points_against_score,omitempty" description:"Championship points awarded for points against, included in total_score. Omitted unless scored."`
+	PotentialPointsScore float64  `json:"potential_points_score,omitempty" description:"Championship points awarded for potential points, included in total_score. Omitted unless scored."`
+	EfficiencyScore      float64  `json:"efficiency_score,omitempty" description:"Championship points awarded for lineup efficiency, included in total_score. Omitted unless scored."`
+
+	DivisionID     string  `json:"division_id,omitempty" description:"MFL division ID. Omitted in leagues without divisions."`
+	Division       string  `json:"division,omitempty" description:"Division name. Omitted in leagues without divisions."`
+	DivisionWinner bool    `json:"division_winner,omitempty" description:"Whether the franchise tops its division's own championship table."`
+	DivisionBonus  float64 `json:"division_bonus,omitempty" description:"Championship points awarded for winning the division, included in total_score."`
+
+	LeagueRank       int      `json:"league_rank,omitempty" description:"Position in the league-wide championship table. Only set in division tables."`
+	LeagueTotalScore *float64 `json:"league_total_score,omitempty" description:"Championship points in the league-wide table, division bonus included. Only set in division tables."`
+}
+
+type RecordV1 struct {
+	Wins   int `json:"wins" description:"Games won."`
+	Losses int `json:"losses" description:"Games lost."`
+	Ties   int `json:"ties" description:"Games tied."`
+}
+
+type AllPlayV1 struct {
+	Wins       int     `json:"wins" description:"Weekly AllPlay wins."`
+	Losses     int     `json:"losses" description:"Weekly AllPlay losses."`
+	Ties       int     `json:"ties" description:"Weekly AllPlay ties."`
+	Percentage float64 `json:"percentage" description:"AllPlay win percentage between 0 and 1."`
+}
+
+// newStandingsResponseV1 maps scored standings onto the v1 contract. Names the display policy
+// hides are left out entirely rather than blanked, so they can't leak through the JSON output.
+func newStandingsResponseV1(standings Standings, policy DisplayPolicy, now time.Time) StandingsResponseV1 {
+	year, _ := convertStringToInteger(LeagueYear) //nolint:errcheck // LeagueYear is a constant.
+
+	response := StandingsResponseV1{
+		SchemaVersion: ResponseSchemaVersion,
+		Metadata: ResponseMetadataV1{
+			LeagueID:     LeagueID,
+			LeagueName:   standings.LeagueName,
+			Year:         year,
+			Week:         completedWeek(standings.Franchises),
+			GeneratedAt:  now.UTC(),
+			DataAsOf:     standings.FetchedAt.UTC(),
+			Warnings:     append([]string{}, standings.Warnings...),
+			DisplayMode:  string(policy.Mode),
+			LeagueFormat: string(standings.Scoring.Format),
+			RecordMetric: string(standings.Scoring.RecordMetric),
+		},
+		Franchises: make([]FranchiseStandingV1, 0, len(standings.Franchises.Franchise)),
+	}
+	divisions := divisionNames(standings.Divisions, standings.Conferences)
+
+	for i, franchise := range applyDisplayPolicy(standings.Franchises, policy).Franchise {
+		standing := FranchiseStandingV1{
+			Rank:        i + 1,
+			FranchiseID: franchise.TeamID,
+			DisplayName: displayLabel(franchise),
+			TeamName:    franchise.TeamName,
+			OwnerName:   franchise.OwnerName,
+			OwnerID:     franchise.OwnerID,
+			OwnerAlias:  franchise.OwnerAlias,
+			Record: RecordV1{
+				Wins: franchise.RecordWins, Losses: franchise.RecordLosses, Ties: franchise.RecordTies,
+			},
+			PointsFor:      franchise.PointsFor,
+			PointsScore:    franchise.PointScore,
+			RecordScore:    franchise.RecordScore,
+			TotalScore:     franchise.TotalScore,
+			DivisionID:     franchise.Division,
+			DivisionWinner: franchise.DivisionWinner,
+			DivisionBonus:  franchise.DivisionBonus,
+		}
+		if division, ok := divisions[franchise.Division]; ok {
+			standing.Division = division.Name
+		}
+
+		if franchise.MedianRecord != "" {
+			standing.MedianRecord = &RecordV1{
+				Wins: franchise.MedianWins, Losses: franchise.MedianLosses, Ties: franchise.MedianTies,
+			}
+		}
+
+		if franchise.PointsAgainstString != "" {
+			pointsAgainst := franchise.PointsAgainst
+			standing.PointsAgainst = &pointsAgainst
+		}
+		if franchise.PotentialPointsString != "" {
+			potentialPoints, efficiency := franchise.PotentialPoints, franchise.LineupEfficiency
+			standing.PotentialPoints = &potentialPoints
+			standing.LineupEfficiency = &efficiency
+		}
+		standing.PointsAgainstScore = franchise.PointsAgainstScore
+		standing.PotentialPointsScore = franchise.PotentialPointsScore
+		standing.EfficiencyScore = franchise.EfficiencyScore
+
+		if franchise.AllPlayPercentageString != "" {
+			standing.AllPlay = &AllPlayV1{
+				Wins:       franchise.AllPlayWins,
+				Losses:     franchise.AllPlayLosses,
+				Ties:       franchise.AllPlayTies,
+				Percentage: franchise.AllPlayPercentage,
+			}
+		}
+		if luck, ok := luckIndex(franchise); ok {
+			standing.Luck = &luck
+		}
+
+		response.Franchises = append(response.Franchises, standing)
+	}
+
+	return response
+}
+
+// completedWeek infers the last completed week from the most games any franchise has played. Points-only
+// leagues have no head to head games, so there it counts AllPlay results instead: every franchise meets
+// each of the others once a week.
+func completedWeek(franchises Franchises) int {
+	week, allPlayGames := 0, 0
+	for _, franchise := range franchises.Franchise {
+		week = max(week, franchise.RecordWins+franchise.RecordLosses+franchise.RecordTies)
+		allPlayGames = max(allPlayGames, franchise.AllPlayWins+franchise.AllPlayLosses+franchise.AllPlayTies)
+	}
+
+	if opponents := len(franchises.Franchise) - 1; week == 0 && opponents > 0 {
+		week = allPlayGames / opponents
+	}
+
+	return week
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testStandings() Standings {
+	return Standings{
+		Franchises: Franchises{
+			Franchise: []Franchise{
+				{TeamID: "0001", TeamName: Team1Name, OwnerName: Team1Owner, RecordWins: 9, RecordLosses: 3,
+					PointsFor: 1500.5, PointScore: 2, RecordScore: 2, TotalScore: 4, AllPlayWins: 100,
+					AllPlayLosses: 32, AllPlayPercentageString: ".758", AllPlayPercentage: 0.758},
+				{TeamID: "0002", TeamName: Team2Name, OwnerName: Team2Owner, RecordWins: 3, RecordLosses: 8,
+					RecordTies: 1, PointsFor: 1200, PointScore: 1, RecordScore: 1, TotalScore: 2},
+			},
+		},
+		Warnings:   []string{"AllPlay data unavailable"},
+		LeagueName: "Test League",
+		FetchedAt:  time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestNewStandingsResponseV1(t *testing.T) {
+	now := time.Date(2025, 12, 1, 12, 0, 5, 0, time.UTC)
+	result := newStandingsResponseV1(testStandings(), DisplayPolicy{Mode: DisplayFull}, now)
+
+	expected := `{
+		"schema_version": "v1",
+		"metadata": {
+			"league_id": "15781",
+			"league_name": "Test League",
+			"year": 2025,
+			"week": 12,
+			"generated_at": "2025-12-01T12:00:05Z",
+			"data_as_of": "2025-12-01T12:00:00Z",
+			"warnings": ["AllPlay data unavailable"],
+			"display_mode": "full"
+		},
+		"franchises": [
+			{
+				"rank": 1, "franchise_id": "0001", "display_name": "Team 1", "team_name": "Team 1", "owner_name": "Owner 1",
+				"record": {"wins": 9, "losses": 3, "ties": 0},
+				"points_for": 1500.5, "points_score": 2, "record_score": 2, "total_score": 4,
+				"all_play": {"wins": 100, "losses": 32, "ties": 0, "percentage": 0.758}, "luck": -0.1
+			},
+			{
+				"rank": 2, "franchise_id": "0002", "display_name": "Team 2", "team_name": "Team 2", "owner_name": "Owner 2",
+				"record": {"wins": 3, "losses": 8, "ties": 1},
+				"points_for": 1200, "points_score": 1, "record_score": 1, "total_score": 2
+			}
+		]
+	}`
+
+	JSONCompare(t, result, expected)
+}
+
+func TestNewStandingsResponseV1HidesNames(t *testing.T) {
+	body, err := json.Marshal(newStandingsResponseV1(testStandings(), DisplayPolicy{Mode: DisplayTeamID}, time.Now()))
+	if err != nil {
+		t.Fatalf("Failed to marshal response: %v", err)
+	}
+
+	for _, hidden := range []string{Team1Name, Team2Name, Team1Owner, Team2Owner, "team_name", "owner_name"} {
+		assert.NotContains(t, string(body), hidden)
+	}
+}
+
+func TestCompletedWeek(t *testing.T) {
+	assert.Equal(t, 0, completedWeek(Franchises{}))
+	assert.Equal(t, 12, completedWeek(testStandings().Franchises))
+
+	pointsOnly := Franchises{Franchise: []Franchise{
+		{TeamID: "0001", AllPlayWins: 20, AllPlayLosses: 7},
+		{TeamID: "0002", AllPlayWins: 12, AllPlayLosses: 14, AllPlayTies: 1},
+		{TeamID: "0003", AllPlayWins: 7, AllPlayLosses: 20},
+		{TeamID: "0004", AllPlayWins: 15, AllPlayLosses: 12},
+	}}
+	assert.Equal(t, 9, completedWeek(pointsOnly), "27 AllPlay games against 3 opponents")
+}
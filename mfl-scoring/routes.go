@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type routeHandler func(ctx context.Context, request events.APIGatewayProxyRequest,
+	params map[string]string) (events.APIGatewayProxyResponse, error)
+
+// route matches the tail of the request path, so "/health" serves both /mfl-scoring/health and
+// /stage/mfl-scoring/health. Segments wrapped in braces are captured as parameters.
+type route struct {
+	method  string
+	pattern string
+	handler routeHandler
+}
+
+func routes() []route {
+	return []route{
+		{method: http.MethodGet, pattern: "/health", handler: serveHealth},
+		{method: http.MethodGet, pattern: "/schema/v1", handler: serveSchema},
+		{method: http.MethodGet, pattern: "/openapi.json", handler: serveOpenAPI},
+		{method: http.MethodGet, pattern: "/franchise/{id}", handler: serveFranchise},
+		{method: http.MethodGet, pattern: "/scenarios", handler: serveScenarios},
+		{method: http.MethodGet, pattern: "/projections", handler: serveProjections},
+		{method: http.MethodPost, pattern: "/what-if", handler: serveWhatIf},
+		{method: http.MethodGet, pattern: "/recap", handler: serveRecap},
+		{method: http.MethodGet, pattern: "/divisions", handler: serveDivisions},
+		{method: http.MethodGet, pattern: "/schedule-strength", handler: serveScheduleStrength},
+		{method: http.MethodGet, pattern: "/power-rankings", handler: servePowerRankings},
+		{method: http.MethodGet, pattern: "/dynasty", handler: serveDynasty},
+		{method: http.MethodGet, pattern: "/playoffs", handler: servePlayoffs},
+	}
+}
+
+// matchRoute returns the handler for the request, or nil when the default standings table should
+// be served.
+func matchRoute(table []route, method, path string) (routeHandler, map[string]string) {
+	pathSegments := splitPath(path)
+
+	for _, r := range table {
+		if method != "" && !strings.EqualFold(r.method, method) {
+			continue
+		}
+
+		patternSegments := splitPath(r.pattern)
+		if len(patternSegments) > len(pathSegments) {
+			continue
+		}
+
+		tail := pathSegments[len(pathSegments)-len(patternSegments):]
+		params := map[string]string{}
+		matched := true
+		for i, segment := range patternSegments {
+			if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+				params[strings.Trim(segment, "{}")] = tail[i]
+				continue
+			}
+			if segment != tail[i] {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return r.handler, params
+		}
+	}
+
+	return nil, nil
+}
+
+func splitPath(path string) []string {
+	return strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchRoute(t *testing.T) {
+	var called string
+	handlerFor := func(name string) routeHandler {
+		return func(_ context.Context, _ events.APIGatewayProxyRequest,
+			_ map[string]string) (events.APIGatewayProxyResponse, error) {
+			called = name
+			return events.APIGatewayProxyResponse{}, nil
+		}
+	}
+	table := []route{
+		{method: http.MethodGet, pattern: "/health", handler: handlerFor("health")},
+		{method: http.MethodGet, pattern: "/franchise/{id}", handler: handlerFor("franchise")},
+	}
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		expectedRoute  string
+		expectedParams map[string]string
+	}{
+		{name: "custom domain", method: http.MethodGet, path: "/mfl-scoring/health", expectedRoute: "health",
+			expectedParams: map[string]string{}},
+		{name: "stage prefix", method: http.MethodGet, path: "/stage/mfl-scoring/health/", expectedRoute: "health",
+			expectedParams: map[string]string{}},
+		{name: "path parameter", method: http.MethodGet, path: "/mfl-scoring/franchise/0003",
+			expectedRoute: "franchise", expectedParams: map[string]string{"id": "0003"}},
+		{name: "default", method: http.MethodGet, path: "/mfl-scoring"},
+		{name: "wrong method", method: http.MethodPost, path: "/mfl-scoring/health"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			called = ""
+			handle, params := matchRoute(table, tc.method, tc.path)
+			if tc.expectedRoute == "" {
+				assert.Nil(t, handle)
+				return
+			}
+			if handle == nil {
+				t.Fatalf("Expected route %s to match %s", tc.expectedRoute, tc.path)
+			}
+			_, _ = handle(context.Background(), events.APIGatewayProxyRequest{}, params) //nolint
+			assert.Equal(t, tc.expectedRoute, called)
+			assert.Equal(t, tc.expectedParams, params)
+		})
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+type ScenarioStatus string
+
+const (
+	ScenarioClinched   ScenarioStatus = "clinched"
+	ScenarioEliminated ScenarioStatus = "eliminated"
+	ScenarioAlive      ScenarioStatus = "alive"
+)
+
+type ScenariosResponseV1 struct {
+	SchemaVersion  string                `json:"schema_version" description:"Version of this response schema."`
+	Metadata       ResponseMetadataV1    `json:"metadata" description:"Where the standings came from and how fresh they are."`
+	GamesRemaining int                   `json:"games_remaining" description:"Head to head games left on the schedule."`
+	WeeklyCeiling  *float64              `json:"weekly_ceiling,omitempty" description:"Best single-game score so far, assumed to be the most anyone can score in a remaining game. Omitted before any games are played."`
+	Franchises     []FranchiseScenarioV1 `json:"franchises" description:"Scenarios in current championship order."`
+}
+
+// FranchiseScenarioV1 bounds the championship points a franchise can still finish with. The bounds
+// treat every other result as free, so they are never tighter than reality: clinched and
+// eliminated are certain, as long as nobody beats the season's best weekly score, while alive may
+// include a franchise that can no longer win in practice.
+type FranchiseScenarioV1 struct {
+	Rank            int            `json:"rank" description:"Current championship position, 1 is first."`
+	FranchiseID     string         `json:"franchise_id" description:"MFL franchise ID, e.g. 0003."`
+	DisplayName     string         `json:"display_name" description:"Label to show for the franchise under the display policy."`
+	Status          ScenarioStatus `json:"status" description:"clinched, eliminated or alive."`
+	GamesRemaining  int            `json:"games_remaining" description:"Games this franchise has left."`
+	TotalScore      float64        `json:"total_score" description:"Current championship points."`
+	BestCase        float64        `json:"best_case" description:"Most championship points still reachable."`
+	WorstCase       float64        `json:"worst_case" description:"Fewest championship points still possible."`
+	ClinchTarget    float64        `json:"clinch_target" description:"Most championship points any rival can still reach. Finishing above it clinches."`
+	EliminationLine float64        `json:"elimination_line" description:"Most championship points any rival is guaranteed. Finishing below it eliminates."`
+	Summary         string         `json:"summary" description:"What the franchise needs, in a sentence."`
+}
+
+// scenarioBounds is what one franchise can still do: the extremes of its fantasy points place and
+// record place, given the games left.
+type scenarioBounds struct {
+	best, worst float64
+}
+
+// projectScenarios works out each franchise's best and worst case from the scored standings and
+// the league schedule.
+func projectScenarios(franchises []Franchise, games []Game, scoring ScoringConfig) []FranchiseScenarioV1 {
+	remaining := remainingGames(games)
+	ceiling := weeklyCeiling(games)
+
+	gamesLeft := map[string]int{}
+	for _, game := range remaining {
+		gamesLeft[game.Home]++
+		gamesLeft[game.Away]++
+	}
+
+	bounds := make([]scenarioBounds, len(franchises))
+	for i, franchise := range franchises {
+		bounds[i] = boundScenario(franchises, franchise, gamesLeft, ceiling, scoring)
+		// Once the season is over the division titles are settled; until then any franchise in a
+		// division might still win it, and nobody is sure to.
+		if len(remaining) == 0 {
+			bounds[i].best += franchise.DivisionBonus
+			bounds[i].worst += franchise.DivisionBonus
+		} else if franchise.Division != "" {
+			bounds[i].best += scoring.DivisionWinnerBonus
+		}
+		// Points against, potential points and efficiency all move with games left anywhere in the
+		// league, so until the season is over any place is possible.
+		for _, component := range scoring.Components {
+			if len(remaining) == 0 {
+				bounds[i].best += componentScore(franchise, component.Metric)
+				bounds[i].worst += componentScore(franchise, component.Metric)
+			} else {
+				bounds[i].best += float64(len(franchises))
+				bounds[i].worst++
+			}
+		}
+	}
+
+	scenarios := make([]FranchiseScenarioV1, 0, len(franchises))
+	for i, franchise := range franchises {
+		scenario := FranchiseScenarioV1{
+			Rank:           i + 1,
+			FranchiseID:    franchise.TeamID,
+			DisplayName:    displayLabel(franchise),
+			GamesRemaining: gamesLeft[franchise.TeamID],
+			TotalScore:     franchise.TotalScore,
+			BestCase:       bounds[i].best,
+			WorstCase:      bounds[i].worst,
+		}
+
+		for j := range franchises {
+			if j == i {
+				continue
+			}
+			scenario.ClinchTarget = max(scenario.ClinchTarget, bounds[j].best)
+			scenario.EliminationLine = max(scenario.EliminationLine, bounds[j].worst)
+		}
+
+		switch {
+		case len(remaining) == 0 && i == 0:
+			scenario.Status = ScenarioClinched
+			scenario.Summary = fmt.Sprintf("%s won the championship.", scenario.DisplayName)
+		case len(remaining) == 0:
+			scenario.Status = ScenarioEliminated
+			scenario.Summary = fmt.Sprintf("%s finished %s.", scenario.DisplayName, ordinal(scenario.Rank))
+		case scenario.WorstCase > scenario.ClinchTarget:
+			scenario.Status = ScenarioClinched
+		case scenario.BestCase < scenario.EliminationLine:
+			scenario.Status = ScenarioEliminated
+		default:
+			scenario.Status = ScenarioAlive
+		}
+		if scenario.Summary == "" {
+			scenario.Summary = summarizeScenario(scenario)
+		}
+
+		scenarios = append(scenarios, scenario)
+	}
+
+	return scenarios
+}
+
+// boundScenario gives the franchise's best case (it wins out with the top weekly score while every
+// rival stalls) and worst case (it loses out and scores nothing more while every rival wins out
+// with the top weekly score). Ties are scored the way calculatePointsScore splits them. Points-only
+// leagues have no record place to bound.
+func boundScenario(franchises []Franchise, franchise Franchise, gamesLeft map[string]int,
+	ceiling float64, scoring ScoringConfig) scenarioBounds {
+	teams := len(franchises)
+	mine := gamesLeft[franchise.TeamID]
+	best := projectRecords(franchises, franchise.TeamID, gamesLeft, scoring, true)
+	worst := projectRecords(franchises, franchise.TeamID, gamesLeft, scoring, false)
+
+	var pointsBest, pointsWorst, recordBest, recordWorst placeCount
+	var bestRecord, worstRecord float64
+	for i, other := range franchises {
+		if other.TeamID == franchise.TeamID {
+			bestRecord, worstRecord = best[i].RecordMagic, worst[i].RecordMagic
+		}
+	}
+	for i, other := range franchises {
+		if other.TeamID == franchise.TeamID {
+			continue
+		}
+		theirs := gamesLeft[other.TeamID]
+
+		pointsBest.add(other.PointsFor, reachablePoints(franchise.PointsFor, mine, ceiling))
+		pointsWorst.add(reachablePoints(other.PointsFor, theirs, ceiling), franchise.PointsFor)
+		recordBest.add(best[i].RecordMagic, bestRecord)
+		recordWorst.add(worst[i].RecordMagic, worstRecord)
+	}
+
+	if scoring.Format == LeaguePointsOnly {
+		return scenarioBounds{best: pointsBest.score(teams), worst: pointsWorst.score(teams)}
+	}
+
+	return scenarioBounds{
+		best:  pointsBest.score(teams) + recordBest.score(teams),
+		worst: pointsWorst.score(teams) + recordWorst.score(teams),
+	}
+}
+
+// projectRecords rescores the records with the franchise winning (or losing) every game it has
+// left and every rival doing the opposite. Each game is also a result against the median, which
+// only counts when the league scores it.
+func projectRecords(franchises []Franchise, id string, gamesLeft map[string]int, scoring ScoringConfig,
+	wins bool) []Franchise {
+	projected := append([]Franchise{}, franchises...)
+	for i := range projected {
+		f := &projected[i]
+		games := gamesLeft[f.TeamID]
+		if (f.TeamID == id) == wins {
+			f.RecordWins += games
+			f.MedianWins += games
+		} else {
+			f.RecordLosses += games
+			f.MedianLosses += games
+		}
+	}
+
+	return calculateRecordMagic(Franchises{Franchise: projected}, scoring).Franchise
+}
+
+func reachablePoints(pointsFor float64, games int, ceiling float64) float64 {
+	if games == 0 {
+		return pointsFor
+	}
+
+	return pointsFor + float64(games)*ceiling
+}
+
+// weeklyCeiling is the best single-game score so far. Before any games are played there is nothing
+// to go on, so scoring is unbounded.
+func weeklyCeiling(games []Game) float64 {
+	ceiling := math.Inf(1)
+	for _, game := range games {
+		if !game.Played {
+			continue
+		}
+		if math.IsInf(ceiling, 1) {
+			ceiling = 0
+		}
+		ceiling = max(ceiling, game.HomeScore, game.AwayScore)
+	}
+
+	return ceiling
+}
+
+// placeCount tracks how many franchises finish above or level with the one being bounded.
+type placeCount struct {
+	above, tied int
+}
+
+func (p *placeCount) add(theirs, mine float64) {
+	switch {
+	case theirs > mine:
+		p.above++
+	case theirs == mine:
+		p.tied++
+	}
+}
+
+// score is the championship points for the place, with tied franchises sharing the places they
+// cover.
+func (p placeCount) score(teams int) float64 {
+	place := p.above + 1
+	return float64(teams-place+1) - float64(p.tied)/2
+}
+
+func summarizeScenario(scenario FranchiseScenarioV1) string {
+	switch scenario.Status {
+	case ScenarioClinched:
+		return fmt.Sprintf("%s has clinched: even the worst case of %s beats the best any rival can reach (%s).",
+			scenario.DisplayName, formatScore(scenario.WorstCase), formatScore(scenario.ClinchTarget))
+	case ScenarioEliminated:
+		return fmt.Sprintf("%s is eliminated: its best case of %s is below the %s a rival is guaranteed.",
+			scenario.DisplayName, formatScore(scenario.BestCase), formatScore(scenario.EliminationLine))
+	default:
+		return fmt.Sprintf("%s can finish with %s to %s championship points. It needs at least %s to stay "+
+			"alive and more than %s to be sure of first.", scenario.DisplayName, formatScore(scenario.WorstCase),
+			formatScore(scenario.BestCase), formatScore(scenario.EliminationLine), formatScore(scenario.ClinchTarget))
+	}
+}
+
+func printScenarioTable(scenarios []FranchiseScenarioV1) string {
+	t := table.NewWriter()
+	t.SetOutputMirror(&bytes.Buffer{})
+	t.AppendHeader(table.Row{"Team", "Status", TotalPts, "Best Case", "Worst Case", "Games Left"})
+	for _, s := range scenarios {
+		t.AppendRow(table.Row{s.DisplayName, s.Status, formatScore(s.TotalScore), formatScore(s.BestCase),
+			formatScore(s.WorstCase), strconv.Itoa(s.GamesRemaining)})
+	}
+
+	return t.Render()
+}
+
+func serveScenarios(ctx context.Context, request events.APIGatewayProxyRequest,
+	_ map[string]string) (events.APIGatewayProxyResponse, error) {
+	format, err := jsonOrTextFormat(request)
+	if err != nil {
+		return textResponse(http.StatusNotAcceptable, err.Error()), nil
+	}
+
+	standings, policy, err := standingsForRoute(ctx, "/scenarios", request)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	games, err := fetchSchedule(ctx)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	scenarios := projectScenarios(applyDisplayPolicy(standings.Franchises, policy).Franchise, games,
+		standings.Scoring)
+	if format == FormatJSON {
+		response := ScenariosResponseV1{
+			SchemaVersion:  ResponseSchemaVersion,
+			Metadata:       newStandingsResponseV1(standings, policy, time.Now()).Metadata,
+			GamesRemaining: len(remainingGames(games)),
+			Franchises:     scenarios,
+		}
+		if ceiling := weeklyCeiling(games); !math.IsInf(ceiling, 1) {
+			response.WeeklyCeiling = &ceiling
+		}
+		return jsonResponse(http.StatusOK, response, "application/json")
+	}
+
+	body := printScenarioTable(scenarios) + "\n"
+	for _, scenario := range scenarios {
+		body += "\n" + scenario.Summary
+	}
+
+	return textResponse(http.StatusOK, body+formatWarnings(standings.Warnings)), nil
+}
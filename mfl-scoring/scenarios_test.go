@@ -0,0 +1,124 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func scenarioFranchises() []Franchise {
+	return applyDisplayPolicy(scoredTiedStandings().Franchises, DisplayPolicy{Mode: DisplayFull}).Franchise
+}
+
+func TestProjectScenariosSeasonOver(t *testing.T) {
+	scenarios := projectScenarios(scenarioFranchises(), nil, ScoringConfig{})
+
+	assert.Equal(t, ScenarioClinched, scenarios[0].Status)
+	assert.Equal(t, "B won the championship.", scenarios[0].Summary)
+	for _, scenario := range scenarios[1:] {
+		assert.Equal(t, ScenarioEliminated, scenario.Status)
+	}
+	for _, scenario := range scenarios {
+		assert.Equal(t, scenario.TotalScore, scenario.BestCase, scenario.DisplayName)
+		assert.Equal(t, scenario.TotalScore, scenario.WorstCase, scenario.DisplayName)
+	}
+}
+
+func TestProjectScenariosWithGamesLeft(t *testing.T) {
+	// Standings going in: B 7.0 (3-0, 90 pts), A 5.0 (1-2, 100 pts), C 4.0 and D 4.0 (2-1, 80 pts).
+	games := []Game{{Week: 4, Home: "0002", Away: "0001"}, {Week: 4, Home: "0003", Away: "0004"}}
+
+	scenarios := projectScenarios(scenarioFranchises(), games, ScoringConfig{})
+	byID := map[string]FranchiseScenarioV1{}
+	for _, scenario := range scenarios {
+		byID[scenario.FranchiseID] = scenario
+	}
+
+	b := byID["0002"]
+	assert.Equal(t, ScenarioAlive, b.Status)
+	assert.Equal(t, 1, b.GamesRemaining)
+	assert.Equal(t, 8.0, b.BestCase, "wins out and outscores everyone")
+	// With no games played yet scoring is unbounded, so everyone with a game left can pass B on
+	// points, and C and D can both reach B's three wins.
+	assert.Equal(t, 1.0+3.0, b.WorstCase)
+
+	for _, scenario := range scenarios {
+		assert.LessOrEqual(t, scenario.WorstCase, scenario.TotalScore, scenario.DisplayName)
+		assert.GreaterOrEqual(t, scenario.BestCase, scenario.TotalScore, scenario.DisplayName)
+	}
+}
+
+func TestProjectScenariosClinchAndElimination(t *testing.T) {
+	franchises := []Franchise{
+		{TeamID: "0001", PointsFor: 1000, RecordWins: 10, RecordMagic: 10, TotalScore: 6},
+		{TeamID: "0002", PointsFor: 500, RecordWins: 5, RecordMagic: 5, TotalScore: 4},
+		{TeamID: "0003", PointsFor: 400, RecordWins: 2, RecordMagic: 2, TotalScore: 2},
+	}
+	// Only 0002 and 0003 still play, once, and nobody has scored more than 60 in a week, so 0001's
+	// points and record lead can't be caught.
+	games := []Game{
+		{Week: 13, Home: "0001", Away: "0002", HomeScore: 60, AwayScore: 40, Played: true},
+		{Week: 14, Home: "0002", Away: "0003"},
+	}
+
+	scenarios := projectScenarios(franchises, games, ScoringConfig{})
+
+	assert.Equal(t, ScenarioClinched, scenarios[0].Status)
+	assert.Equal(t, 6.0, scenarios[0].WorstCase)
+	assert.Equal(t, 4.0, scenarios[0].ClinchTarget)
+	assert.Equal(t, ScenarioEliminated, scenarios[1].Status)
+	assert.Equal(t, ScenarioEliminated, scenarios[2].Status)
+	assert.Contains(t, scenarios[2].Summary, "is eliminated")
+}
+
+func TestWeeklyCeiling(t *testing.T) {
+	assert.True(t, math.IsInf(weeklyCeiling([]Game{{Week: 1}}), 1))
+	assert.Equal(t, 131.5, weeklyCeiling([]Game{
+		{Week: 1, HomeScore: 99, AwayScore: 131.5, Played: true},
+		{Week: 2, HomeScore: 120, AwayScore: 80, Played: true},
+		{Week: 3},
+	}))
+}
+
+func TestPlaceCountScore(t *testing.T) {
+	assert.Equal(t, 10.0, placeCount{}.score(10))
+	assert.Equal(t, 9.0, placeCount{tied: 2}.score(10), "first through third split 10+9+8")
+	assert.Equal(t, 1.0, placeCount{above: 9}.score(10))
+}
+
+func TestProjectScenariosPointsOnly(t *testing.T) {
+	franchises := []Franchise{
+		{TeamID: "0001", PointsFor: 1000, RecordWins: 9, PointScore: 2, TotalScore: 2},
+		{TeamID: "0002", PointsFor: 900, PointScore: 1, TotalScore: 1},
+	}
+	games := []Game{
+		{Week: 1, Home: "0001", Away: "0002", HomeScore: 60, AwayScore: 40, Played: true},
+		{Week: 2, Home: "0001", Away: "0002"},
+	}
+
+	scenarios := projectScenarios(franchises, games, ScoringConfig{Format: LeaguePointsOnly})
+
+	assert.Equal(t, 2.0, scenarios[0].BestCase, "records don't score")
+	assert.Equal(t, 2.0, scenarios[0].WorstCase, "0002 can't make up 100 points with one 60 point game")
+	assert.Equal(t, ScenarioClinched, scenarios[0].Status)
+}
+
+func TestProjectScenariosWinPercentage(t *testing.T) {
+	// 0001 is 3-0 with a game left and 0002 is 2-1 with none, so only 0001's percentage can move.
+	franchises := []Franchise{
+		{TeamID: "0001", PointsFor: 300, RecordWins: 3, RecordMagic: 1, TotalScore: 4},
+		{TeamID: "0002", PointsFor: 200, RecordWins: 2, RecordLosses: 1, RecordMagic: 2.0 / 3, TotalScore: 2},
+	}
+	games := []Game{
+		{Week: 1, Home: "0001", Away: "0002", HomeScore: 100, AwayScore: 90, Played: true},
+		{Week: 4, Home: "0001", Away: "0003"},
+	}
+
+	scenarios := projectScenarios(franchises, games, ScoringConfig{RecordMetric: RecordWinPercentage})
+
+	// Losing out leaves 0001 at .750, still ahead of 0002's .667.
+	assert.Equal(t, 4.0, scenarios[0].WorstCase)
+	assert.Equal(t, ScenarioClinched, scenarios[0].Status)
+	assert.Equal(t, ScenarioEliminated, scenarios[1].Status)
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ScheduleResponse struct {
+	Schedule Schedule `json:"schedule"`
+}
+
+type Schedule struct {
+	WeeklySchedule oneOrMany[WeeklySchedule] `json:"weeklySchedule"`
+}
+
+type WeeklySchedule struct {
+	Week    string             `json:"week"`
+	Matchup oneOrMany[Matchup] `json:"matchup"`
+}
+
+type Matchup struct {
+	Franchise []MatchupFranchise `json:"franchise"`
+}
+
+type MatchupFranchise struct {
+	ID     string `json:"id"`
+	Score  string `json:"score"`
+	Result string `json:"result"`
+	IsHome string `json:"isHome"`
+}
+
+// oneOrMany decodes MFL list fields, which are written as a bare object when they hold a single
+// element and as an array otherwise.
+type oneOrMany[T any] []T
+
+func (o *oneOrMany[T]) UnmarshalJSON(data []byte) error {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var many []T
+		if err := json.Unmarshal(data, &many); err != nil {
+			return err
+		}
+		*o = many
+		return nil
+	}
+	if trimmed == "null" {
+		*o = nil
+		return nil
+	}
+
+	var one T
+	if err := json.Unmarshal(data, &one); err != nil {
+		return err
+	}
+	*o = oneOrMany[T]{one}
+
+	return nil
+}
+
+// Game is one head to head matchup. Scores are only meaningful once Played is set.
+type Game struct {
+	Week      int
+	Home      string
+	Away      string
+	HomeScore float64
+	AwayScore float64
+	Played    bool
+}
+
+func scheduleAPIURL(baseURL, apiKey string) string {
+	return baseURL + LeagueYear + "/" + LeagueAPIPath + ScheduleAPIQuery + "&" +
+		LeagueIDQuery + "&" + APIOutputTypeQuery + "&APIKEY=" + apiKey
+}
+
+func getSchedule(client HTTPClient, scheduleAPIURL string) (ScheduleResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, scheduleAPIURL, http.NoBody)
+	if err != nil {
+		return ScheduleResponse{}, err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return ScheduleResponse{}, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		return ScheduleResponse{}, err
+	}
+
+	var scheduleResponse ScheduleResponse
+	err = json.Unmarshal(responseData, &scheduleResponse)
+	if err != nil {
+		return ScheduleResponse{}, err
+	}
+
+	return scheduleResponse, nil
+}
+
+// scheduleGames flattens the schedule into games. A game counts as played once MFL has recorded a
+// result for it.
+func scheduleGames(schedule ScheduleResponse) ([]Game, error) {
+	var games []Game
+
+	for _, week := range schedule.Schedule.WeeklySchedule {
+		weekNumber, err := convertStringToInteger(week.Week)
+		if err != nil {
+			return nil, fmt.Errorf("schedule week %q: %w", week.Week, err)
+		}
+
+		for _, matchup := range week.Matchup {
+			if len(matchup.Franchise) != 2 {
+				return nil, fmt.Errorf("schedule week %d has a matchup with %d franchises", weekNumber,
+					len(matchup.Franchise))
+			}
+
+			home, away := matchup.Franchise[0], matchup.Franchise[1]
+			if away.IsHome == "1" {
+				home, away = away, home
+			}
+
+			game := Game{Week: weekNumber, Home: home.ID, Away: away.ID, Played: home.Result != ""}
+			if game.Played {
+				if game.HomeScore, err = strconv.ParseFloat(home.Score, 64); err != nil {
+					return nil, fmt.Errorf("schedule week %d score %q: %w", weekNumber, home.Score, err)
+				}
+				if game.AwayScore, err = strconv.ParseFloat(away.Score, 64); err != nil {
+					return nil, fmt.Errorf("schedule week %d score %q: %w", weekNumber, away.Score, err)
+				}
+			}
+
+			games = append(games, game)
+		}
+	}
+
+	return games, nil
+}
+
+// fetchSchedule gets the league schedule for the endpoints that look at games still to be played.
+func fetchSchedule(ctx context.Context) ([]Game, error) {
+	apiKey, err := getAPIKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	url := scheduleAPIURL(MflURL, apiKey)
+	loggerFromContext(ctx).Debug("fetching schedule", slog.String("url", redactAPIKey(url, apiKey)))
+	_, span := startSpan(ctx, "fetch.schedule")
+	fetchStart := time.Now()
+	schedule, err := getSchedule(&http.Client{}, url)
+	logUpstream(ctx, "schedule", fetchStart, err)
+	span.End(err)
+	if err != nil {
+		telemetryFromContext(ctx).Count(MetricUpstreamErrors, 1)
+		return nil, err
+	}
+
+	return scheduleGames(schedule)
+}
+
+func remainingGames(games []Game) []Game {
+	var remaining []Game
+	for _, game := range games {
+		if !game.Played {
+			remaining = append(remaining, game)
+		}
+	}
+
+	return remaining
+}
+
+// gamesByWeek groups games by week, keeping the weeks and the games within them in schedule order.
+func gamesByWeek(games []Game) [][]Game {
+	var weeks [][]Game
+	index := map[int]int{}
+	for _, game := range games {
+		i, ok := index[game.Week]
+		if !ok {
+			i = len(weeks)
+			index[game.Week] = i
+			weeks = append(weeks, nil)
+		}
+		weeks[i] = append(weeks[i], game)
+	}
+
+	return weeks
+}
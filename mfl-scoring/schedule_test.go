@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// scheduleJSON is shaped like MFL's TYPE=schedule export: week 1 is played, week 2 is not, and
+// week 2's single matchup is a bare object rather than an array.
+const scheduleJSON = `{"schedule":{"weeklySchedule":[
+	{"week":"1","matchup":[
+		{"franchise":[{"id":"0001","isHome":"0","score":"101.5","result":"W"},
+			{"id":"0002","isHome":"1","score":"99.0","result":"L"}]},
+		{"franchise":[{"id":"0003","isHome":"1","score":"88.25","result":"T"},
+			{"id":"0004","isHome":"0","score":"88.25","result":"T"}]}]},
+	{"week":"2","matchup":
+		{"franchise":[{"id":"0001","isHome":"1"},{"id":"0003","isHome":"0"}]}}
+]}}`
+
+func TestScheduleGames(t *testing.T) {
+	var schedule ScheduleResponse
+	assert.NoError(t, json.Unmarshal([]byte(scheduleJSON), &schedule))
+
+	games, err := scheduleGames(schedule)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Game{
+		{Week: 1, Home: "0002", Away: "0001", HomeScore: 99, AwayScore: 101.5, Played: true},
+		{Week: 1, Home: "0003", Away: "0004", HomeScore: 88.25, AwayScore: 88.25, Played: true},
+		{Week: 2, Home: "0001", Away: "0003"},
+	}, games)
+	assert.Equal(t, []Game{{Week: 2, Home: "0001", Away: "0003"}}, remainingGames(games))
+}
+
+func TestScheduleGamesRejectsMalformedMatchups(t *testing.T) {
+	var schedule ScheduleResponse
+	assert.NoError(t, json.Unmarshal([]byte(`{"schedule":{"weeklySchedule":{"week":"3","matchup":
+		{"franchise":[{"id":"0001","isHome":"1"}]}}}}`), &schedule))
+
+	_, err := scheduleGames(schedule)
+	assert.ErrorContains(t, err, "week 3 has a matchup with 1 franchises")
+}
+
+func TestOneOrManyNull(t *testing.T) {
+	var schedule ScheduleResponse
+	assert.NoError(t, json.Unmarshal([]byte(`{"schedule":{"weeklySchedule":null}}`), &schedule))
+	assert.Empty(t, schedule.Schedule.WeeklySchedule)
+}
+
+func TestGetSchedule(t *testing.T) {
+	mockHTTPClient := new(MockHTTPClient)
+	mockHTTPClient.On("Do", mock.Anything).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(scheduleJSON)),
+	}, nil)
+
+	result, err := getSchedule(mockHTTPClient, scheduleAPIURL("http://example.com/", "key"))
+
+	assert.NoError(t, err)
+	mockHTTPClient.AssertExpectations(t)
+	assert.Len(t, result.Schedule.WeeklySchedule, 2)
+	assert.Equal(t, "http://example.com/2025/export?TYPE=schedule&L=15781&JSON=1&APIKEY=key",
+		mockHTTPClient.Calls[0].Arguments.Get(0).(*http.Request).URL.String())
+}
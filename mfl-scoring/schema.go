@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const (
+	jsonSchemaDialect string = "https://json-schema.org/draft/2020-12/schema"
+	openAPIVersion    string = "3.1.0"
+)
+
+// JSONSchema is the subset of JSON Schema the response DTOs need.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	ID                   string                 `json:"$id,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Const                string                 `json:"const,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// jsonSchemaFor generates a schema from a Go type using its json tags, with descriptions taken from
+// the description tag. Fields tagged omitempty are optional; everything else is required.
+func jsonSchemaFor(t reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: "array", Items: jsonSchemaFor(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object"}
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		return &JSONSchema{}
+	}
+}
+
+func structSchema(t reflect.Type) *JSONSchema {
+	closed := false
+	schema := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}, AdditionalProperties: &closed}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := jsonSchemaFor(field.Type)
+		property.Description = field.Tag.Get("description")
+		schema.Properties[name] = property
+
+		if !strings.Contains(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
+
+// standingsSchemaV1 documents StandingsResponseV1 as a standalone JSON Schema.
+func standingsSchemaV1() *JSONSchema {
+	schema := jsonSchemaFor(reflect.TypeOf(StandingsResponseV1{}))
+	schema.Schema = jsonSchemaDialect
+	schema.ID = "https://mfl-scoring.timkelsch.com/mfl-scoring/schema/" + ResponseSchemaVersion
+	schema.Title = "MFL championship standings " + ResponseSchemaVersion
+	schema.Properties["schema_version"].Const = ResponseSchemaVersion
+
+	return schema
+}
+
+// openAPIDocument describes the standings endpoint with the v1 schema embedded as its response.
+func openAPIDocument() map[string]any {
+	schema := standingsSchemaV1()
+	schema.Schema = ""
+	schema.ID = ""
+
+	return map[string]any{
+		"openapi": openAPIVersion,
+		"info": map[string]any{
+			"title":   "MFL Scoring API",
+			"version": ResponseSchemaVersion,
+		},
+		"paths": map[string]any{
+			"/mfl-scoring": map[string]any{
+				"get": map[string]any{
+					"summary": "Championship standings",
+					"parameters": []map[string]any{{
+						"name": "output", "in": "query", "required": false,
+						"schema": map[string]any{"type": "string", "enum": supportedFormats()},
+					}, {
+						"name": "explain", "in": "query", "required": false,
+						"description": "Set to 1 for every franchise's score breakdown instead of the table.",
+						"schema":      map[string]any{"type": "string", "enum": []string{"1"}},
+					}},
+					"responses": map[string]any{
+						"200": map[string]any{
+							"description": "Standings in championship order, or their breakdowns with ?explain=1",
+							"content": map[string]any{
+								"application/json": map[string]any{
+									"schema": map[string]any{"oneOf": []map[string]string{
+										{"$ref": "#/components/schemas/StandingsResponseV1"},
+										{"$ref": "#/components/schemas/ExplanationsResponseV1"},
+									}},
+								},
+							},
+						},
+					},
+				},
+			},
+			"/mfl-scoring/franchise/{id}": jsonOrTextOperation("Explain one franchise's championship score",
+				"FranchiseExplanationV1", map[string]any{
+					"name": "id", "in": "path", "required": true, "schema": map[string]string{"type": "string"},
+				}),
+			"/mfl-scoring/scenarios": jsonOrTextOperation("Clinch and elimination scenarios", "ScenariosResponseV1"),
+			"/mfl-scoring/projections": jsonOrTextOperation("Monte Carlo projection of the final standings",
+				"ProjectionsResponseV1",
+				map[string]any{"name": "simulations", "in": "query", "required": false,
+					"schema": map[string]any{"type": "integer", "minimum": 1, "maximum": maxSimulations}},
+				map[string]any{"name": "seed", "in": "query", "required": false,
+					"schema": map[string]any{"type": "integer", "minimum": 0}}),
+			"/mfl-scoring/recap": recapOperation(),
+			"/mfl-scoring/divisions": jsonOrTextOperation("Championship standings within each division",
+				"DivisionsResponseV1"),
+			"/mfl-scoring/schedule-strength": jsonOrTextOperation("Strength of schedule and luck",
+				"ScheduleStrengthResponseV1"),
+			"/mfl-scoring/power-rankings": jsonOrTextOperation("Power rankings from the weekly results",
+				"PowerRankingsResponseV1"),
+			"/mfl-scoring/dynasty": jsonOrTextOperation("Championship points summed across seasons",
+				"DynastyResponseV1"),
+			"/mfl-scoring/playoffs": jsonOrTextOperation("Playoff seeding and bracket if the season ended now",
+				"PlayoffsResponseV1"),
+			"/mfl-scoring/what-if": map[string]any{
+				"post": map[string]any{
+					"summary": "Recompute the standings with hypothetical results",
+					"requestBody": map[string]any{
+						"required": true,
+						"content": map[string]any{
+							"application/json": map[string]any{
+								"schema": map[string]string{"$ref": "#/components/schemas/WhatIfRequestV1"},
+							},
+						},
+					},
+					"responses": jsonOrTextResponses("Hypothetical standings with the change from the real table",
+						"WhatIfResponseV1"),
+				},
+			},
+		},
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"StandingsResponseV1":        schema,
+				"FranchiseExplanationV1":     jsonSchemaFor(reflect.TypeOf(FranchiseExplanationV1{})),
+				"ExplanationsResponseV1":     jsonSchemaFor(reflect.TypeOf(ExplanationsResponseV1{})),
+				"ScenariosResponseV1":        jsonSchemaFor(reflect.TypeOf(ScenariosResponseV1{})),
+				"ProjectionsResponseV1":      jsonSchemaFor(reflect.TypeOf(ProjectionsResponseV1{})),
+				"WhatIfRequestV1":            jsonSchemaFor(reflect.TypeOf(WhatIfRequestV1{})),
+				"WhatIfResponseV1":           jsonSchemaFor(reflect.TypeOf(WhatIfResponseV1{})),
+				"RecapResponseV1":            jsonSchemaFor(reflect.TypeOf(RecapResponseV1{})),
+				"DivisionsResponseV1":        jsonSchemaFor(reflect.TypeOf(DivisionsResponseV1{})),
+				"ScheduleStrengthResponseV1": jsonSchemaFor(reflect.TypeOf(ScheduleStrengthResponseV1{})),
+				"PowerRankingsResponseV1":    jsonSchemaFor(reflect.TypeOf(PowerRankingsResponseV1{})),
+				"DynastyResponseV1":          jsonSchemaFor(reflect.TypeOf(DynastyResponseV1{})),
+				"PlayoffsResponseV1":         jsonSchemaFor(reflect.TypeOf(PlayoffsResponseV1{})),
+			},
+		},
+	}
+}
+
+// jsonOrTextOperation documents a GET endpoint that answers with a JSON document or, by default,
+// plain text.
+func jsonOrTextOperation(summary, schemaName string, parameters ...map[string]any) map[string]any {
+	parameters = append(parameters, map[string]any{
+		"name": "output", "in": "query", "required": false,
+		"schema": map[string]any{"type": "string", "enum": []string{"json", "text"}},
+	})
+
+	return map[string]any{
+		"get": map[string]any{
+			"summary":    summary,
+			"parameters": parameters,
+			"responses":  jsonOrTextResponses(summary, schemaName),
+		},
+	}
+}
+
+// recapOperation documents GET /recap, which answers in Markdown by default.
+func recapOperation() map[string]any {
+	responses := jsonOrTextResponses("Weekly recap", "RecapResponseV1")
+	content := responses["200"].(map[string]any)["content"].(map[string]any)
+	content["text/markdown"] = map[string]any{"schema": map[string]string{"type": "string"}}
+
+	return map[string]any{
+		"get": map[string]any{
+			"summary": "Weekly recap for the league message board",
+			"parameters": []map[string]any{
+				{"name": "week", "in": "query", "required": false,
+					"schema": map[string]any{"type": "integer", "minimum": 1}},
+				{"name": "output", "in": "query", "required": false,
+					"schema": map[string]any{"type": "string", "enum": []string{"markdown", "text", "json"}}},
+			},
+			"responses": responses,
+		},
+	}
+}
+
+func jsonOrTextResponses(description, schemaName string) map[string]any {
+	return map[string]any{
+		"200": map[string]any{
+			"description": description,
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": map[string]string{"$ref": "#/components/schemas/" + schemaName},
+				},
+				"text/plain": map[string]any{"schema": map[string]string{"type": "string"}},
+			},
+		},
+	}
+}
+
+func serveSchema(_ context.Context, _ events.APIGatewayProxyRequest,
+	_ map[string]string) (events.APIGatewayProxyResponse, error) {
+	return jsonResponse(http.StatusOK, standingsSchemaV1(), "application/schema+json")
+}
+
+func serveOpenAPI(_ context.Context, _ events.APIGatewayProxyRequest,
+	_ map[string]string) (events.APIGatewayProxyResponse, error) {
+	return jsonResponse(http.StatusOK, openAPIDocument(), "application/json")
+}
+
+func jsonResponse(statusCode int, v any, contentType string) (events.APIGatewayProxyResponse, error) {
+	body, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	return events.APIGatewayProxyResponse{
+		Headers:    map[string]string{"content-type": contentType},
+		Body:       string(body),
+		StatusCode: statusCode,
+	}, nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONSchemaForPrimitives(t *testing.T) {
+	testCases := []struct {
+		value    any
+		expected JSONSchema
+	}{
+		{value: "", expected: JSONSchema{Type: "string"}},
+		{value: 1, expected: JSONSchema{Type: "integer"}},
+		{value: 1.5, expected: JSONSchema{Type: "number"}},
+		{value: true, expected: JSONSchema{Type: "boolean"}},
+		{value: time.Time{}, expected: JSONSchema{Type: "string", Format: "date-time"}},
+		{value: []string{}, expected: JSONSchema{Type: "array", Items: &JSONSchema{Type: "string"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(reflect.TypeOf(tc.value).String(), func(t *testing.T) {
+			assert.Equal(t, &tc.expected, jsonSchemaFor(reflect.TypeOf(tc.value)))
+		})
+	}
+}
+
+// Every field in the v1 contract must be documented, and only omitempty fields may be optional.
+func TestStandingsSchemaV1IsDocumented(t *testing.T) {
+	var walk func(path string, schema *JSONSchema)
+	walk = func(path string, schema *JSONSchema) {
+		for name, property := range schema.Properties {
+			if property.Description == "" {
+				t.Errorf("%s.%s has no description", path, name)
+			}
+			walk(path+"."+name, property)
+		}
+		if schema.Items != nil {
+			walk(path+"[]", schema.Items)
+		}
+	}
+	walk("$", standingsSchemaV1())
+
+	franchise := standingsSchemaV1().Properties["franchises"].Items
+	required := append([]string(nil), franchise.Required...)
+	sort.Strings(required)
+	assert.Equal(t, []string{"display_name", "franchise_id", "points_for", "points_score", "rank", "record", "record_score",
+		"total_score"}, required)
+}
+
+// A marshaled response must only use properties the schema declares.
+func TestStandingsResponseMatchesSchema(t *testing.T) {
+	body, err := json.Marshal(newStandingsResponseV1(testStandings(), DisplayPolicy{Mode: DisplayFull}, time.Now()))
+	if err != nil {
+		t.Fatalf("Failed to marshal response: %v", err)
+	}
+
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	var check func(path string, value any, schema *JSONSchema)
+	check = func(path string, value any, schema *JSONSchema) {
+		switch v := value.(type) {
+		case map[string]any:
+			for _, name := range schema.Required {
+				if _, ok := v[name]; !ok {
+					t.Errorf("%s is missing required property %s", path, name)
+				}
+			}
+			for name, child := range v {
+				property, ok := schema.Properties[name]
+				if !ok {
+					t.Errorf("%s.%s is not in the schema", path, name)
+					continue
+				}
+				check(path+"."+name, child, property)
+			}
+		case []any:
+			for _, child := range v {
+				check(path+"[]", child, schema.Items)
+			}
+		}
+	}
+	check("$", document, standingsSchemaV1())
+}
+
+func TestServeSchemaAndOpenAPI(t *testing.T) {
+	response, err := serveSchema(context.Background(), events.APIGatewayProxyRequest{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Contains(t, response.Body, `"const": "v1"`)
+
+	response, err = serveOpenAPI(context.Background(), events.APIGatewayProxyRequest{}, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, response.Body, `"openapi": "3.1.0"`)
+	assert.Contains(t, response.Body, `"$ref": "#/components/schemas/StandingsResponseV1"`)
+	assert.Contains(t, response.Body, `"$ref": "#/components/schemas/FranchiseExplanationV1"`)
+	assert.Contains(t, response.Body, `"$ref": "#/components/schemas/ExplanationsResponseV1"`)
+	assert.Contains(t, response.Body, `"ExplanationsResponseV1": {`)
+	assert.Contains(t, response.Body, `"$ref": "#/components/schemas/ScenariosResponseV1"`)
+}
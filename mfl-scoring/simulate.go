@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+const (
+	defaultSimulations int = 10000
+	maxSimulations     int = 50000
+)
+
+type ProjectionsResponseV1 struct {
+	SchemaVersion  string                  `json:"schema_version" description:"Version of this response schema."`
+	Metadata       ResponseMetadataV1      `json:"metadata" description:"Where the standings came from and how fresh they are."`
+	Simulations    int                     `json:"simulations" description:"Number of simulated seasons."`
+	Seed           uint64                  `json:"seed" description:"Random seed. Pass it back as ?seed= to reproduce these numbers."`
+	GamesRemaining int                     `json:"games_remaining" description:"Head to head games left on the schedule."`
+	Franchises     []FranchiseProjectionV1 `json:"franchises" description:"Projections in current championship order."`
+}
+
+type FranchiseProjectionV1 struct {
+	Rank                    int       `json:"rank" description:"Current championship position, 1 is first."`
+	FranchiseID             string    `json:"franchise_id" description:"MFL franchise ID, e.g. 0003."`
+	DisplayName             string    `json:"display_name" description:"Label to show for the franchise under the display policy."`
+	WeeklyMean              float64   `json:"weekly_mean" description:"Mean weekly score the simulation draws from."`
+	WeeklyStdDev            float64   `json:"weekly_std_dev" description:"Standard deviation of the weekly score the simulation draws from."`
+	ExpectedTotalScore      float64   `json:"expected_total_score" description:"Average final championship points across simulations."`
+	ChampionshipProbability float64   `json:"championship_probability" description:"Share of simulations finishing first, between 0 and 1."`
+	PositionProbabilities   []float64 `json:"position_probabilities" description:"Share of simulations finishing in each position; index 0 is first."`
+}
+
+// weeklyModel is the normal distribution a franchise's weekly score is drawn from.
+type weeklyModel struct {
+	mean, stdDev float64
+}
+
+// weeklyModels fits each franchise's played weekly scores. Franchises with fewer than two games use
+// the league-wide spread, and franchises with none use the league-wide mean as well.
+func weeklyModels(games []Game) map[string]weeklyModel {
+	scores := map[string][]float64{}
+	var league []float64
+	for _, game := range games {
+		if !game.Played {
+			continue
+		}
+		scores[game.Home] = append(scores[game.Home], game.HomeScore)
+		scores[game.Away] = append(scores[game.Away], game.AwayScore)
+		league = append(league, game.HomeScore, game.AwayScore)
+	}
+
+	leagueModel := fitWeeklyModel(league)
+	models := map[string]weeklyModel{"": leagueModel}
+	for id, franchiseScores := range scores {
+		model := fitWeeklyModel(franchiseScores)
+		if len(franchiseScores) < 2 {
+			model.stdDev = leagueModel.stdDev
+		}
+		models[id] = model
+	}
+
+	return models
+}
+
+func fitWeeklyModel(scores []float64) weeklyModel {
+	if len(scores) == 0 {
+		return weeklyModel{}
+	}
+
+	var sum float64
+	for _, score := range scores {
+		sum += score
+	}
+	model := weeklyModel{mean: sum / float64(len(scores))}
+
+	if len(scores) > 1 {
+		var squares float64
+		for _, score := range scores {
+			squares += (score - model.mean) * (score - model.mean)
+		}
+		model.stdDev = math.Sqrt(squares / float64(len(scores)-1))
+	}
+
+	return model
+}
+
+func modelFor(models map[string]weeklyModel, id string) weeklyModel {
+	if model, ok := models[id]; ok {
+		return model
+	}
+
+	return models[""]
+}
+
+// simulateSeason plays out the remaining games runs times from the current standings and replays
+// the championship scoring after each, counting where every franchise finishes. Simulated weeks
+// are also scored against their median when the league counts it. AllPlay percentage
+// isn't simulated, so the current value still breaks ties on fantasy points. The same seed always
+// gives the same result.
+func simulateSeason(franchises []Franchise, games []Game, scoring ScoringConfig, runs int,
+	seed uint64) []FranchiseProjectionV1 {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	models := weeklyModels(games)
+	remaining := remainingGames(games)
+
+	index := map[string]int{}
+	projections := make([]FranchiseProjectionV1, len(franchises))
+	for i, franchise := range franchises {
+		index[franchise.TeamID] = i
+		model := modelFor(models, franchise.TeamID)
+		projections[i] = FranchiseProjectionV1{
+			Rank:                  i + 1,
+			FranchiseID:           franchise.TeamID,
+			DisplayName:           displayLabel(franchise),
+			WeeklyMean:            roundFloat(model.mean, 2),
+			WeeklyStdDev:          roundFloat(model.stdDev, 2),
+			PositionProbabilities: make([]float64, len(franchises)),
+		}
+	}
+
+	weeks := gamesByWeek(remaining)
+	simulated := make([]Franchise, len(franchises))
+	for run := 0; run < runs; run++ {
+		copy(simulated, franchises)
+		for _, week := range weeks {
+			played := make([]Game, 0, len(week))
+			for _, game := range week {
+				home, homeOK := index[game.Home]
+				away, awayOK := index[game.Away]
+				if !homeOK || !awayOK {
+					continue
+				}
+				game.HomeScore = drawScore(rng, modelFor(models, game.Home))
+				game.AwayScore = drawScore(rng, modelFor(models, game.Away))
+				playGame(&simulated[home], &simulated[away], game.HomeScore, game.AwayScore)
+				played = append(played, game)
+			}
+			if scoring.scoresMedian() {
+				playMedianWeek(simulated, index, played)
+			}
+		}
+
+		final := rankFranchises(scoreChampionship(Franchises{Franchise: simulated}, scoring), scoring)
+		for position, franchise := range final.Franchise {
+			projection := &projections[index[franchise.TeamID]]
+			projection.PositionProbabilities[position]++
+			projection.ExpectedTotalScore += franchise.TotalScore
+		}
+	}
+
+	for i := range projections {
+		for position := range projections[i].PositionProbabilities {
+			projections[i].PositionProbabilities[position] /= float64(runs)
+		}
+		projections[i].ChampionshipProbability = projections[i].PositionProbabilities[0]
+		projections[i].ExpectedTotalScore = roundFloat(projections[i].ExpectedTotalScore/float64(runs), 2)
+	}
+
+	return projections
+}
+
+// drawScore samples a weekly score, rounded to hundredths like MFL's and never negative.
+func drawScore(rng *rand.Rand, model weeklyModel) float64 {
+	return math.Max(0, roundFloat(model.mean+rng.NormFloat64()*model.stdDev, 2))
+}
+
+func playGame(home, away *Franchise, homeScore, awayScore float64) {
+	home.PointsFor = roundFloat(home.PointsFor+homeScore, 2)
+	away.PointsFor = roundFloat(away.PointsFor+awayScore, 2)
+	home.PointsAgainst = roundFloat(home.PointsAgainst+awayScore, 2)
+	away.PointsAgainst = roundFloat(away.PointsAgainst+homeScore, 2)
+
+	switch {
+	case homeScore > awayScore:
+		home.RecordWins++
+		away.RecordLosses++
+	case awayScore > homeScore:
+		away.RecordWins++
+		home.RecordLosses++
+	default:
+		home.RecordTies++
+		away.RecordTies++
+	}
+}
+
+// simulationParameters reads ?simulations= and ?seed=. Without a seed one is picked from the clock
+// and reported back so the run can be repeated.
+func simulationParameters(query map[string]string, now time.Time) (int, uint64, error) {
+	runs := defaultSimulations
+	if value, ok := query["simulations"]; ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxSimulations {
+			return 0, 0, fmt.Errorf("simulations must be a whole number from 1 to %d", maxSimulations)
+		}
+		runs = parsed
+	}
+
+	seed := uint64(now.UnixNano())
+	if value, ok := query["seed"]; ok {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0, 0, errors.New("seed must be a non-negative whole number")
+		}
+		seed = parsed
+	}
+
+	return runs, seed, nil
+}
+
+func printProjectionTable(projections []FranchiseProjectionV1) string {
+	t := table.NewWriter()
+	t.SetOutputMirror(&bytes.Buffer{})
+
+	header := table.Row{"Team", "Exp Pts"}
+	for position := range projections {
+		header = append(header, ordinal(position+1))
+	}
+	t.AppendHeader(header)
+
+	for _, p := range projections {
+		row := table.Row{p.DisplayName, strconv.FormatFloat(p.ExpectedTotalScore, 'f', 1, 64)}
+		for _, probability := range p.PositionProbabilities {
+			row = append(row, strconv.FormatFloat(probability*100, 'f', 1, 64)+"%")
+		}
+		t.AppendRow(row)
+	}
+
+	return t.Render()
+}
+
+func serveProjections(ctx context.Context, request events.APIGatewayProxyRequest,
+	_ map[string]string) (events.APIGatewayProxyResponse, error) {
+	format, err := jsonOrTextFormat(request)
+	if err != nil {
+		return textResponse(http.StatusNotAcceptable, err.Error()), nil
+	}
+
+	runs, seed, err := simulationParameters(request.QueryStringParameters, time.Now())
+	if err != nil {
+		return textResponse(http.StatusBadRequest, err.Error()), nil
+	}
+
+	standings, policy, err := standingsForRoute(ctx, "/projections", request)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	games, err := fetchSchedule(ctx)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	_, span := startSpan(ctx, "simulate")
+	span.SetAttribute("simulations", runs)
+	projections := simulateSeason(applyDisplayPolicy(standings.Franchises, policy).Franchise, games,
+		standings.Scoring, runs, seed)
+	span.End(nil)
+
+	if format == FormatJSON {
+		return jsonResponse(http.StatusOK, ProjectionsResponseV1{
+			SchemaVersion:  ResponseSchemaVersion,
+			Metadata:       newStandingsResponseV1(standings, policy, time.Now()).Metadata,
+			Simulations:    runs,
+			Seed:           seed,
+			GamesRemaining: len(remainingGames(games)),
+			Franchises:     projections,
+		}, "application/json")
+	}
+
+	body := printProjectionTable(projections) +
+		fmt.Sprintf("\n%d simulated seasons, seed %d.", runs, seed)
+
+	return textResponse(http.StatusOK, body+formatWarnings(standings.Warnings)), nil
+}
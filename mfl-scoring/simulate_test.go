@@ -0,0 +1,102 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func simulationGames() []Game {
+	return []Game{
+		{Week: 1, Home: "0001", Away: "0002", HomeScore: 120, AwayScore: 80, Played: true},
+		{Week: 1, Home: "0003", Away: "0004", HomeScore: 100, AwayScore: 90, Played: true},
+		{Week: 2, Home: "0001", Away: "0003", HomeScore: 130, AwayScore: 95, Played: true},
+		{Week: 2, Home: "0002", Away: "0004", HomeScore: 85, AwayScore: 88, Played: true},
+		{Week: 3, Home: "0001", Away: "0004"},
+		{Week: 3, Home: "0002", Away: "0003"},
+	}
+}
+
+func TestWeeklyModels(t *testing.T) {
+	models := weeklyModels(simulationGames())
+
+	assert.Equal(t, 125.0, models["0001"].mean)
+	assert.InDelta(t, 7.07, models["0001"].stdDev, 0.01)
+	assert.Equal(t, 98.5, models[""].mean)
+	assert.Equal(t, models[""], modelFor(models, "0099"))
+
+	single := weeklyModels(simulationGames()[:1])
+	assert.Equal(t, single[""].stdDev, single["0001"].stdDev, "one game borrows the league spread")
+}
+
+func TestSimulateSeasonIsReproducible(t *testing.T) {
+	franchises := scenarioFranchises()
+
+	first := simulateSeason(franchises, simulationGames(), ScoringConfig{}, 500, 42)
+	second := simulateSeason(franchises, simulationGames(), ScoringConfig{}, 500, 42)
+	other := simulateSeason(franchises, simulationGames(), ScoringConfig{}, 500, 43)
+
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, other)
+	assert.Equal(t, scenarioFranchises(), franchises, "the input standings are left alone")
+}
+
+func TestSimulateSeasonProbabilities(t *testing.T) {
+	projections := simulateSeason(scenarioFranchises(), simulationGames(), ScoringConfig{}, 1000, 7)
+
+	positionTotals := make([]float64, len(projections))
+	for _, projection := range projections {
+		var sum float64
+		for position, probability := range projection.PositionProbabilities {
+			sum += probability
+			positionTotals[position] += probability
+		}
+		assert.InDelta(t, 1, sum, 1e-9, projection.DisplayName)
+		assert.Equal(t, projection.PositionProbabilities[0], projection.ChampionshipProbability)
+	}
+	for _, total := range positionTotals {
+		assert.InDelta(t, 1, total, 1e-9)
+	}
+}
+
+func TestSimulateSeasonWithNothingLeft(t *testing.T) {
+	played := simulationGames()[:4]
+
+	projections := simulateSeason(scenarioFranchises(), played, ScoringConfig{}, 10, 1)
+
+	for i, projection := range projections {
+		assert.Equal(t, 1.0, projection.PositionProbabilities[i], projection.DisplayName)
+		assert.Equal(t, scenarioFranchises()[i].TotalScore, projection.ExpectedTotalScore)
+	}
+}
+
+func TestPlayGame(t *testing.T) {
+	home, away := Franchise{PointsFor: 100}, Franchise{PointsFor: 90}
+
+	playGame(&home, &away, 80.5, 80.25)
+	playGame(&home, &away, 70, 70)
+
+	assert.Equal(t, Franchise{PointsFor: 250.5, PointsAgainst: 150.25, RecordWins: 1, RecordTies: 1}, home)
+	assert.Equal(t, Franchise{PointsFor: 240.25, PointsAgainst: 150.5, RecordLosses: 1, RecordTies: 1}, away)
+}
+
+func TestSimulationParameters(t *testing.T) {
+	now := time.Unix(0, 1234)
+
+	runs, seed, err := simulationParameters(map[string]string{}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultSimulations, runs)
+	assert.Equal(t, uint64(1234), seed)
+
+	runs, seed, err = simulationParameters(map[string]string{"simulations": "200", "seed": "9"}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, runs)
+	assert.Equal(t, uint64(9), seed)
+
+	for _, query := range []map[string]string{{"simulations": "0"}, {"simulations": "500000"},
+		{"simulations": "many"}, {"seed": "-1"}} {
+		_, _, err := simulationParameters(query, now)
+		assert.Error(t, err, query)
+	}
+}
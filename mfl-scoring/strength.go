@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+type ScheduleStrengthResponseV1 struct {
+	SchemaVersion string               `json:"schema_version" description:"Version of this response schema."`
+	Metadata      ResponseMetadataV1   `json:"metadata" description:"Where the standings came from and how fresh they are."`
+	Franchises    []ScheduleStrengthV1 `json:"franchises" description:"Schedule strength in current championship order."`
+}
+
+// ScheduleStrengthV1 is how tough a franchise's schedule has been and will be, and how lucky it has
+// been with it.
+type ScheduleStrengthV1 struct {
+	Rank         int                `json:"rank" description:"Current championship position, 1 is first."`
+	FranchiseID  string             `json:"franchise_id" description:"MFL franchise ID, e.g. 0003."`
+	DisplayName  string             `json:"display_name" description:"Label to show for the franchise under the display policy."`
+	Past         OpponentStrengthV1 `json:"past" description:"Opponents already played."`
+	Remaining    OpponentStrengthV1 `json:"remaining" description:"Opponents still to play."`
+	ActualWins   float64            `json:"actual_wins" description:"Head to head wins plus half a win per tie."`
+	ExpectedWins float64            `json:"expected_wins" description:"Wins the season AllPlay percentage expects over the games played."`
+	Luck         float64            `json:"luck" description:"Actual wins less expected wins. Positive means the schedule has been kind."`
+}
+
+// OpponentStrengthV1 averages a set of opponents. A franchise met twice counts twice.
+type OpponentStrengthV1 struct {
+	Games           int     `json:"games" description:"Games against these opponents."`
+	OpponentPoints  float64 `json:"opponent_points" description:"Average of the opponents' mean weekly score."`
+	OpponentAllPlay float64 `json:"opponent_all_play" description:"Average of the opponents' AllPlay percentage, between 0 and 1."`
+}
+
+// scheduleStrength rates every franchise's schedule from the weekly results. Opponent strength is
+// each opponent's mean weekly score and AllPlay percentage over the whole season so far, so past and
+// remaining opponents are measured the same way. Luck uses the AllPlay percentage from the same
+// results, so it matches the standings' luck index whenever MFL's AllPlay agrees with the schedule.
+func scheduleStrength(franchises []Franchise, games []Game) []ScheduleStrengthV1 {
+	models := weeklyModels(games)
+
+	allPlay := map[string]RecordV1{}
+	actual := map[string]float64{}
+	for week := 1; week <= latestPlayedWeek(games); week++ {
+		weekGames := playedGamesInWeek(games, week)
+		for id, record := range weeklyAllPlay(weekGames) {
+			allPlay[id] = addRecords(allPlay[id], record)
+		}
+		for _, game := range weekGames {
+			switch {
+			case game.HomeScore > game.AwayScore:
+				actual[game.Home]++
+			case game.AwayScore > game.HomeScore:
+				actual[game.Away]++
+			default:
+				actual[game.Home] += 0.5
+				actual[game.Away] += 0.5
+			}
+		}
+	}
+
+	past := map[string][]string{}
+	remaining := map[string][]string{}
+	for _, game := range games {
+		if game.Played {
+			past[game.Home] = append(past[game.Home], game.Away)
+			past[game.Away] = append(past[game.Away], game.Home)
+		} else {
+			remaining[game.Home] = append(remaining[game.Home], game.Away)
+			remaining[game.Away] = append(remaining[game.Away], game.Home)
+		}
+	}
+
+	opponents := func(ids []string) OpponentStrengthV1 {
+		strength := OpponentStrengthV1{Games: len(ids)}
+		if len(ids) == 0 {
+			return strength
+		}
+		for _, id := range ids {
+			strength.OpponentPoints += modelFor(models, id).mean
+			strength.OpponentAllPlay += winPercentage(allPlay[id])
+		}
+		strength.OpponentPoints = roundFloat(strength.OpponentPoints/float64(len(ids)), 2)
+		strength.OpponentAllPlay = roundFloat(strength.OpponentAllPlay/float64(len(ids)), 3)
+
+		return strength
+	}
+
+	strengths := make([]ScheduleStrengthV1, 0, len(franchises))
+	for i, franchise := range franchises {
+		id := franchise.TeamID
+		expected := expectedWins(winPercentage(allPlay[id]), len(past[id]))
+		strengths = append(strengths, ScheduleStrengthV1{
+			Rank:         i + 1,
+			FranchiseID:  id,
+			DisplayName:  displayLabel(franchise),
+			Past:         opponents(past[id]),
+			Remaining:    opponents(remaining[id]),
+			ActualWins:   actual[id],
+			ExpectedWins: roundFloat(expected, 2),
+			Luck:         roundFloat(actual[id]-expected, 2),
+		})
+	}
+
+	return strengths
+}
+
+// winPercentage is wins plus half a win per tie over games played, for head to head and AllPlay
+// records alike.
+func winPercentage(record RecordV1) float64 {
+	games := record.Wins + record.Losses + record.Ties
+	if games == 0 {
+		return 0
+	}
+
+	return (float64(record.Wins) + float64(record.Ties)/2) / float64(games)
+}
+
+// expectedWins is how many of its games a team with the given AllPlay percentage should have won.
+// Luck, in the standings and on /schedule-strength alike, is head to head wins less this.
+func expectedWins(allPlayPercentage float64, games int) float64 {
+	return allPlayPercentage * float64(games)
+}
+
+// luckIndex is the standings' version of ScheduleStrengthV1.Luck, taken from MFL's record and AllPlay
+// percentage rather than the schedule. It needs both a record and AllPlay data.
+func luckIndex(franchise Franchise) (float64, bool) {
+	games := franchise.RecordWins + franchise.RecordLosses + franchise.RecordTies
+	if franchise.AllPlayPercentageString == "" || games == 0 {
+		return 0, false
+	}
+
+	actual := float64(franchise.RecordWins) + float64(franchise.RecordTies)/2
+	return roundFloat(actual-expectedWins(franchise.AllPlayPercentage, games), 2), true
+}
+
+func printScheduleStrengthTable(strengths []ScheduleStrengthV1) string {
+	t := table.NewWriter()
+	t.SetOutputMirror(&bytes.Buffer{})
+	t.AppendHeader(table.Row{"Team", "Played", "Opp Pts", "Opp AllPlay", "Left", "Left Opp Pts", "Left Opp AllPlay",
+		"Wins", "Exp Wins", "Luck"})
+	for _, s := range strengths {
+		t.AppendRow(table.Row{s.DisplayName, s.Past.Games, formatPoints(s.Past.OpponentPoints),
+			strconv.FormatFloat(s.Past.OpponentAllPlay, 'f', 3, 64), s.Remaining.Games,
+			formatPoints(s.Remaining.OpponentPoints), strconv.FormatFloat(s.Remaining.OpponentAllPlay, 'f', 3, 64),
+			formatScore(s.ActualWins), formatPoints(s.ExpectedWins), formatLuck(s.Luck)})
+	}
+
+	return t.Render()
+}
+
+func formatLuck(luck float64) string {
+	if luck > 0 {
+		return "+" + formatPoints(luck)
+	}
+
+	return formatPoints(luck)
+}
+
+func serveScheduleStrength(ctx context.Context, request events.APIGatewayProxyRequest,
+	_ map[string]string) (events.APIGatewayProxyResponse, error) {
+	format, err := jsonOrTextFormat(request)
+	if err != nil {
+		return textResponse(http.StatusNotAcceptable, err.Error()), nil
+	}
+
+	standings, policy, err := standingsForRoute(ctx, "/schedule-strength", request)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	games, err := fetchSchedule(ctx)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	strengths := scheduleStrength(applyDisplayPolicy(standings.Franchises, policy).Franchise, games)
+	if format == FormatJSON {
+		return jsonResponse(http.StatusOK, ScheduleStrengthResponseV1{
+			SchemaVersion: ResponseSchemaVersion,
+			Metadata:      newStandingsResponseV1(standings, policy, time.Now()).Metadata,
+			Franchises:    strengths,
+		}, "application/json")
+	}
+
+	return textResponse(http.StatusOK, printScheduleStrengthTable(strengths)+formatWarnings(standings.Warnings)), nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Against recapGames the weekly AllPlay records are A 3-0 then 0-3, B 2-1 then 1-1-1, C 1-2 then 3-0
+// and D 0-3 then 1-1-1, and the mean weekly scores are A 85, B 92.5, C 95 and D 87.25.
+func TestScheduleStrength(t *testing.T) {
+	strengths := map[string]ScheduleStrengthV1{}
+	for _, strength := range scheduleStrength(scenarioFranchises(), recapGames()) {
+		strengths[strength.DisplayName] = strength
+	}
+
+	a := strengths["A"]
+	assert.Equal(t, OpponentStrengthV1{Games: 2, OpponentPoints: 93.75, OpponentAllPlay: 0.625}, a.Past)
+	assert.Equal(t, OpponentStrengthV1{Games: 1, OpponentPoints: 87.25, OpponentAllPlay: 0.25}, a.Remaining)
+	assert.Equal(t, 1.0, a.ActualWins)
+	assert.Equal(t, 1.0, a.ExpectedWins)
+	assert.Zero(t, a.Luck)
+
+	assert.Equal(t, 0.5, strengths["B"].ActualWins, "a tie is half a win")
+	assert.Equal(t, 1.17, strengths["B"].ExpectedWins)
+	assert.Equal(t, -0.67, strengths["B"].Luck)
+	assert.Equal(t, 0.67, strengths["C"].Luck)
+	luck, _ := luckIndex(Franchise{RecordLosses: 1, RecordTies: 1, AllPlayPercentageString: ".583",
+		AllPlayPercentage: 3.5 / 6})
+	assert.Equal(t, strengths["B"].Luck, luck, "the standings work luck out the same way")
+
+	none := scheduleStrength(scenarioFranchises(), nil)
+	assert.Equal(t, OpponentStrengthV1{}, none[0].Past, "no schedule, no opponents")
+}
+
+func TestLuckIndex(t *testing.T) {
+	testCases := []struct {
+		name      string
+		franchise Franchise
+		expected  float64
+		ok        bool
+	}{
+		{name: "lucky", franchise: Franchise{RecordWins: 3, RecordLosses: 1, AllPlayPercentageString: ".500",
+			AllPlayPercentage: 0.5}, expected: 1, ok: true},
+		{name: "tie", franchise: Franchise{RecordWins: 1, RecordLosses: 2, RecordTies: 1,
+			AllPlayPercentageString: ".750", AllPlayPercentage: 0.75}, expected: -1.5, ok: true},
+		{name: "no allplay", franchise: Franchise{RecordWins: 3}},
+		{name: "no games", franchise: Franchise{AllPlayPercentageString: ".000"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			luck, ok := luckIndex(tc.franchise)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, luck)
+		})
+	}
+}
+
+func TestPrintScheduleStrengthTable(t *testing.T) {
+	table := printScheduleStrengthTable(scheduleStrength(scenarioFranchises(), recapGames()))
+
+	assert.Contains(t, table, "LEFT OPP ALLPLAY")
+	assert.Contains(t, table, "+0.67")
+	assert.Contains(t, table, "-0.67")
+}
+
+func TestLuckColumn(t *testing.T) {
+	assert.Contains(t, printScoringTableUncouthly(testStandings().Franchises), "LUCK")
+
+	standings := testStandings()
+	for i := range standings.Franchises.Franchise {
+		standings.Franchises.Franchise[i].AllPlayPercentageString = ""
+	}
+	assert.NotContains(t, printScoringTableUncouthly(standings.Franchises), "LUCK")
+	response := newStandingsResponseV1(standings, DisplayPolicy{Mode: DisplayFull}, standings.FetchedAt)
+	assert.Nil(t, response.Franchises[0].Luck)
+}
+
+func TestServeScheduleStrengthRejectsUnsupportedFormatBeforeFetching(t *testing.T) {
+	response, err := serveScheduleStrength(context.Background(), events.APIGatewayProxyRequest{
+		QueryStringParameters: map[string]string{"output": "csv"},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotAcceptable, response.StatusCode)
+}
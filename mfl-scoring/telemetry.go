@@ -0,0 +1,455 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	MetricsNamespace          string = "MflScoring"
+	MetricUpstreamErrors      string = "UpstreamErrors"
+	MetricUnmatchedFranchises string = "UnmatchedFranchises"
+	MetricSecretCacheHits     string = "SecretCacheHits"
+	defaultOTLPEndpoint       string = "http://localhost:4318"
+	otlpTracesPath            string = "/v1/traces"
+	telemetryServiceName      string = "mfl-scoring"
+	otlpExportTimeout                = 2 * time.Second
+	spanStatusOK              string = "ok"
+	spanStatusError           string = "error"
+	emfUnitCount              string = "Count"
+	emfUnitMilliseconds       string = "Milliseconds"
+	emfDurationMetricSuffix   string = "Duration"
+	tracesExporterEnv         string = "OTEL_TRACES_EXPORTER"
+	tracesExporterEndpointEnv string = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	lambdaFunctionNameEnv     string = "AWS_LAMBDA_FUNCTION_NAME"
+	tracesExporterConsole     string = "console"
+	tracesExporterStdout      string = "stdout"
+	tracesExporterOTLP        string = "otlp"
+)
+
+// Span mirrors the shape of an OpenTelemetry span closely enough to be exported to a collector.
+type Span struct {
+	Name       string         `json:"name"`
+	TraceID    string         `json:"trace_id"`
+	SpanID     string         `json:"span_id"`
+	ParentID   string         `json:"parent_id,omitempty"`
+	StartTime  time.Time      `json:"start_time"`
+	EndTime    time.Time      `json:"end_time"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	Status     string         `json:"status"`
+	Error      string         `json:"error,omitempty"`
+
+	telemetry *Telemetry
+}
+
+type SpanExporter interface {
+	ExportSpans(ctx context.Context, spans []Span) error
+}
+
+// Telemetry collects the spans and counters for a single invocation and flushes them at the end.
+type Telemetry struct {
+	mu        sync.Mutex
+	traceID   string
+	stage     string
+	spans     []Span
+	counters  map[string]float64
+	durations map[string]float64
+	exporter  SpanExporter
+}
+
+type telemetryContextKey struct{}
+type spanContextKey struct{}
+
+func newTelemetry(stage string, exporter SpanExporter) *Telemetry {
+	return &Telemetry{
+		traceID:   randomHex(16),
+		stage:     stage,
+		counters:  map[string]float64{},
+		durations: map[string]float64{},
+		exporter:  exporter,
+	}
+}
+
+func withTelemetry(ctx context.Context, telemetry *Telemetry) context.Context {
+	return context.WithValue(ctx, telemetryContextKey{}, telemetry)
+}
+
+// telemetryFromContext returns the invocation's telemetry, or a throwaway collector when none is
+// attached so instrumented code can run in tests without setup.
+func telemetryFromContext(ctx context.Context) *Telemetry {
+	if telemetry, ok := ctx.Value(telemetryContextKey{}).(*Telemetry); ok {
+		return telemetry
+	}
+
+	return newTelemetry("", nil)
+}
+
+// startSpan opens a child of whatever span is already in ctx.
+func startSpan(ctx context.Context, name string, attributes ...slog.Attr) (context.Context, *Span) {
+	telemetry := telemetryFromContext(ctx)
+	span := &Span{
+		Name:       name,
+		TraceID:    telemetry.traceID,
+		SpanID:     randomHex(8),
+		StartTime:  time.Now(),
+		Attributes: map[string]any{},
+		telemetry:  telemetry,
+	}
+
+	if parent, ok := ctx.Value(spanContextKey{}).(*Span); ok {
+		span.ParentID = parent.SpanID
+	}
+
+	for _, attribute := range attributes {
+		span.Attributes[attribute.Key] = attribute.Value.Any()
+	}
+
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+func (s *Span) SetAttribute(key string, value any) {
+	s.Attributes[key] = value
+}
+
+// End closes the span, records its duration as a metric and marks it failed when err is non-nil.
+func (s *Span) End(err error) {
+	s.endAt(time.Now(), err)
+}
+
+func (s *Span) endAt(end time.Time, err error) {
+	s.EndTime = end
+	s.Status = spanStatusOK
+	if err != nil {
+		s.Status = spanStatusError
+		s.Error = redactError(err)
+	}
+
+	s.telemetry.mu.Lock()
+	defer s.telemetry.mu.Unlock()
+	s.telemetry.spans = append(s.telemetry.spans, *s)
+	s.telemetry.durations[spanMetricName(s.Name)] += float64(end.Sub(s.StartTime).Milliseconds())
+}
+
+func (t *Telemetry) Count(name string, value float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.counters[name] += value
+}
+
+func (t *Telemetry) Spans() []Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Span(nil), t.spans...)
+}
+
+// Flush exports the collected spans and writes the metrics as a CloudWatch Embedded Metric Format
+// document when running in Lambda, or as a debug log line otherwise.
+func (t *Telemetry) Flush(ctx context.Context, metricsOut io.Writer, inLambda bool) {
+	logger := loggerFromContext(ctx)
+
+	if t.exporter != nil {
+		if err := t.exporter.ExportSpans(ctx, t.Spans()); err != nil {
+			logger.Warn("span export failed", slog.String("error", err.Error()))
+		}
+	}
+
+	if !inLambda {
+		t.mu.Lock()
+		logger.Debug("metrics", slog.Any("counters", t.counters), slog.Any("durations_ms", t.durations))
+		t.mu.Unlock()
+		return
+	}
+
+	document, err := t.emfDocument(time.Now())
+	if err != nil {
+		logger.Warn("encoding EMF metrics failed", slog.String("error", err.Error()))
+		return
+	}
+
+	if _, err := fmt.Fprintln(metricsOut, string(document)); err != nil {
+		logger.Warn("writing EMF metrics failed", slog.String("error", err.Error()))
+	}
+}
+
+type emfMetric struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit"`
+}
+
+type emfDirective struct {
+	Namespace  string      `json:"Namespace"`
+	Dimensions [][]string  `json:"Dimensions"`
+	Metrics    []emfMetric `json:"Metrics"`
+}
+
+type emfMetadata struct {
+	Timestamp         int64          `json:"Timestamp"`
+	CloudWatchMetrics []emfDirective `json:"CloudWatchMetrics"`
+}
+
+func (t *Telemetry) emfDocument(now time.Time) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	document := map[string]any{"Stage": t.stage}
+	directive := emfDirective{Namespace: MetricsNamespace, Dimensions: [][]string{{"Stage"}}}
+
+	// Always report the counters so dashboards see zeros rather than gaps.
+	for _, name := range []string{MetricUpstreamErrors, MetricUnmatchedFranchises, MetricSecretCacheHits} {
+		if _, ok := t.counters[name]; !ok {
+			t.counters[name] = 0
+		}
+	}
+
+	for _, name := range sortedKeys(t.counters) {
+		directive.Metrics = append(directive.Metrics, emfMetric{Name: name, Unit: emfUnitCount})
+		document[name] = t.counters[name]
+	}
+
+	for _, name := range sortedKeys(t.durations) {
+		directive.Metrics = append(directive.Metrics, emfMetric{Name: name, Unit: emfUnitMilliseconds})
+		document[name] = t.durations[name]
+	}
+
+	document["_aws"] = emfMetadata{Timestamp: now.UnixMilli(), CloudWatchMetrics: []emfDirective{directive}}
+
+	return json.Marshal(document)
+}
+
+// spanMetricName turns "fetch.league" into "FetchLeagueDuration".
+func spanMetricName(spanName string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(spanName, func(r rune) bool { return r == '.' || r == '_' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	b.WriteString(emfDurationMetricSuffix)
+
+	return b.String()
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return strings.Repeat("0", n*2)
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// newSpanExporter picks an exporter from the standard OTEL_TRACES_EXPORTER variable. Spans are not
+// exported unless asked for.
+func newSpanExporter() SpanExporter {
+	switch strings.ToLower(os.Getenv(tracesExporterEnv)) {
+	case tracesExporterConsole, tracesExporterStdout:
+		return &writerSpanExporter{w: os.Stdout}
+	case tracesExporterOTLP:
+		endpoint := os.Getenv(tracesExporterEndpointEnv)
+		if endpoint == "" {
+			endpoint = defaultOTLPEndpoint
+		}
+		return &otlpSpanExporter{client: &http.Client{Timeout: otlpExportTimeout}, endpoint: endpoint}
+	default:
+		return nil
+	}
+}
+
+// writerSpanExporter writes one JSON span per line.
+type writerSpanExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (e *writerSpanExporter) ExportSpans(_ context.Context, spans []Span) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	encoder := json.NewEncoder(e.w)
+	for _, span := range spans {
+		if err := encoder.Encode(struct {
+			Type string `json:"type"`
+			Span
+		}{Type: "span", Span: span}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// otlpSpanExporter posts spans to an OpenTelemetry collector using OTLP/HTTP with JSON encoding.
+type otlpSpanExporter struct {
+	client   HTTPClient
+	endpoint string
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTracesRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusOK         = 1
+	otlpStatusError      = 2
+)
+
+func (e *otlpSpanExporter) ExportSpans(ctx context.Context, spans []Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(toOTLPRequest(spans))
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(e.endpoint, "/")+otlpTracesPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := e.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("collector responded with status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+func toOTLPRequest(spans []Span) otlpTracesRequest {
+	scopeSpans := otlpScopeSpans{Scope: otlpScope{Name: telemetryServiceName}}
+
+	for _, span := range spans {
+		converted := otlpSpan{
+			TraceID:           span.TraceID,
+			SpanID:            span.SpanID,
+			ParentSpanID:      span.ParentID,
+			Name:              span.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			Status:            otlpStatus{Code: otlpStatusOK},
+		}
+		if span.Status == spanStatusError {
+			converted.Status = otlpStatus{Code: otlpStatusError, Message: span.Error}
+		}
+		for _, key := range sortedAttributeKeys(span.Attributes) {
+			converted.Attributes = append(converted.Attributes, otlpAttribute(key, span.Attributes[key]))
+		}
+		scopeSpans.Spans = append(scopeSpans.Spans, converted)
+	}
+
+	return otlpTracesRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: []otlpKeyValue{otlpAttribute("service.name", telemetryServiceName)}},
+		ScopeSpans: []otlpScopeSpans{scopeSpans},
+	}}}
+}
+
+func otlpAttribute(key string, value any) otlpKeyValue {
+	var converted otlpAnyValue
+	switch v := value.(type) {
+	case string:
+		converted.StringValue = &v
+	case int:
+		s := strconv.Itoa(v)
+		converted.IntValue = &s
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		converted.IntValue = &s
+	case float64:
+		converted.DoubleValue = &v
+	case bool:
+		converted.BoolValue = &v
+	default:
+		s := fmt.Sprint(v)
+		converted.StringValue = &s
+	}
+
+	return otlpKeyValue{Key: key, Value: converted}
+}
+
+func sortedAttributeKeys(attributes map[string]any) []string {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
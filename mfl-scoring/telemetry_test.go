@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartSpanLinksParent(t *testing.T) {
+	telemetry := newTelemetry("stage", nil)
+	ctx := withTelemetry(context.Background(), telemetry)
+
+	ctx, parent := startSpan(ctx, "handler")
+	_, child := startSpan(ctx, "fetch.league")
+	child.End(nil)
+	parent.End(errors.New("boom"))
+
+	spans := telemetry.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	assert.Equal(t, "fetch.league", spans[0].Name)
+	assert.Equal(t, parent.SpanID, spans[0].ParentID)
+	assert.Equal(t, spans[0].TraceID, spans[1].TraceID)
+	assert.Equal(t, spanStatusOK, spans[0].Status)
+	assert.Equal(t, spanStatusError, spans[1].Status)
+	assert.Equal(t, "boom", spans[1].Error)
+}
+
+func TestSpanRedactsAPIKey(t *testing.T) {
+	telemetry := newTelemetry("stage", nil)
+	_, span := startSpan(withTelemetry(context.Background(), telemetry), "fetch.league")
+	span.End(&url.Error{Op: "Get", URL: "https://www46.myfantasyleague.com/2025/export?TYPE=league&APIKEY=supersecret",
+		Err: errors.New("i/o timeout")})
+
+	spans := telemetry.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	assert.NotContains(t, spans[0].Error, "supersecret")
+	assert.Contains(t, spans[0].Error, "i/o timeout")
+}
+
+func TestSpanMetricName(t *testing.T) {
+	assert.Equal(t, "FetchLeagueDuration", spanMetricName("fetch.league"))
+	assert.Equal(t, "ScrapeAllPlayDuration", spanMetricName("scrape.allPlay"))
+	assert.Equal(t, "HandlerDuration", spanMetricName("handler"))
+}
+
+func TestEMFDocument(t *testing.T) {
+	telemetry := newTelemetry("prod", nil)
+	ctx := withTelemetry(context.Background(), telemetry)
+	_, span := startSpan(ctx, "scoring")
+	span.endAt(span.StartTime.Add(25*time.Millisecond), nil)
+	telemetry.Count(MetricUpstreamErrors, 1)
+	telemetry.Count(MetricUpstreamErrors, 1)
+
+	raw, err := telemetry.emfDocument(time.UnixMilli(1700000000000))
+	if err != nil {
+		t.Fatalf("emfDocument() error = %v", err)
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(raw, &document); err != nil {
+		t.Fatalf("EMF document is not JSON: %v", err)
+	}
+
+	assert.Equal(t, "prod", document["Stage"])
+	assert.InDelta(t, 2.0, document[MetricUpstreamErrors], 0)
+	assert.InDelta(t, 0.0, document[MetricUnmatchedFranchises], 0)
+	assert.InDelta(t, 25.0, document["ScoringDuration"], 0)
+
+	metadata := document["_aws"].(map[string]any)                          //nolint
+	directive := metadata["CloudWatchMetrics"].([]any)[0].(map[string]any) //nolint
+	assert.InDelta(t, 1700000000000.0, metadata["Timestamp"], 0)
+	assert.Equal(t, MetricsNamespace, directive["Namespace"])
+	assert.Len(t, directive["Metrics"], 4)
+}
+
+func TestFlushWritesEMFOnlyInLambda(t *testing.T) {
+	var out bytes.Buffer
+	telemetry := newTelemetry("stage", nil)
+
+	telemetry.Flush(context.Background(), &out, false)
+	assert.Empty(t, out.String())
+
+	telemetry.Flush(context.Background(), &out, true)
+	assert.Contains(t, out.String(), `"CloudWatchMetrics"`)
+}
+
+func TestWriterSpanExporter(t *testing.T) {
+	var out bytes.Buffer
+	exporter := &writerSpanExporter{w: &out}
+
+	err := exporter.ExportSpans(context.Background(), []Span{{Name: "a"}, {Name: "b"}})
+	if err != nil {
+		t.Fatalf("ExportSpans() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"type":"span"`)
+	assert.Contains(t, lines[1], `"name":"b"`)
+}
+
+func TestOTLPSpanExporter(t *testing.T) {
+	var received otlpTracesRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, otlpTracesPath, r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body) //nolint
+		assert.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	exporter := &otlpSpanExporter{client: server.Client(), endpoint: server.URL}
+	start := time.Unix(0, 1000)
+	err := exporter.ExportSpans(context.Background(), []Span{{
+		Name: "fetch.league", TraceID: "t", SpanID: "s", StartTime: start, EndTime: start.Add(time.Microsecond),
+		Attributes: map[string]any{"rows": 10}, Status: spanStatusError, Error: "timeout",
+	}})
+	if err != nil {
+		t.Fatalf("ExportSpans() error = %v", err)
+	}
+
+	spans := received.ResourceSpans[0].ScopeSpans[0].Spans
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "fetch.league", spans[0].Name)
+	assert.Equal(t, "1000", spans[0].StartTimeUnixNano)
+	assert.Equal(t, "2000", spans[0].EndTimeUnixNano)
+	assert.Equal(t, otlpStatusError, spans[0].Status.Code)
+	assert.Equal(t, "10", *spans[0].Attributes[0].Value.IntValue)
+}
+
+func TestNewSpanExporter(t *testing.T) {
+	t.Setenv(tracesExporterEnv, "")
+	assert.Nil(t, newSpanExporter())
+
+	t.Setenv(tracesExporterEnv, tracesExporterConsole)
+	assert.IsType(t, &writerSpanExporter{}, newSpanExporter())
+
+	t.Setenv(tracesExporterEnv, tracesExporterOTLP)
+	exporter, ok := newSpanExporter().(*otlpSpanExporter)
+	assert.True(t, ok)
+	assert.Equal(t, defaultOTLPEndpoint, exporter.endpoint)
+}
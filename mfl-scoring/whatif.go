@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+const (
+	maxHypotheticalGames int     = 100
+	defaultWhatIfMargin  float64 = 0.01
+)
+
+var ErrInvalidWhatIf = errors.New("invalid what-if request")
+
+// WhatIfRequestV1 is the body of POST /what-if.
+type WhatIfRequestV1 struct {
+	Games []HypotheticalGameV1 `json:"games" description:"Results to assume for games not yet played."`
+}
+
+// HypotheticalGameV1 is one assumed result. Without scores the loser scores its season average and
+// the winner wins by Margin, which defaults to the smallest possible win.
+type HypotheticalGameV1 struct {
+	Week        int      `json:"week" description:"Week of the scheduled game."`
+	Winner      string   `json:"winner" description:"Franchise ID of the winner."`
+	Loser       string   `json:"loser" description:"Franchise ID of the loser."`
+	Margin      float64  `json:"margin,omitempty" description:"Points the winner wins by when scores aren't given."`
+	WinnerScore *float64 `json:"winner_score,omitempty" description:"Winner's score. Must be given together with loser_score."`
+	LoserScore  *float64 `json:"loser_score,omitempty" description:"Loser's score. Must be given together with winner_score."`
+}
+
+type WhatIfResponseV1 struct {
+	SchemaVersion string             `json:"schema_version" description:"Version of this response schema."`
+	Metadata      ResponseMetadataV1 `json:"metadata" description:"Where the real standings came from and how fresh they are."`
+	Games         []AppliedGameV1    `json:"games" description:"The assumed results with the scores that were used."`
+	Franchises    []WhatIfStandingV1 `json:"franchises" description:"Hypothetical standings in championship order."`
+}
+
+type AppliedGameV1 struct {
+	Week        int     `json:"week" description:"Week of the game."`
+	Winner      string  `json:"winner" description:"Franchise ID of the winner."`
+	Loser       string  `json:"loser" description:"Franchise ID of the loser."`
+	WinnerScore float64 `json:"winner_score" description:"Winner's score."`
+	LoserScore  float64 `json:"loser_score" description:"Loser's score."`
+}
+
+// WhatIfStandingV1 is a franchise in the hypothetical table alongside where it really stands.
+type WhatIfStandingV1 struct {
+	Standing         FranchiseStandingV1 `json:"standing" description:"The franchise in the hypothetical standings."`
+	ActualRank       int                 `json:"actual_rank" description:"Championship position in the real standings."`
+	ActualTotalScore float64             `json:"actual_total_score" description:"Championship points in the real standings."`
+	RankChange       int                 `json:"rank_change" description:"Places gained, negative when places are lost."`
+	TotalScoreChange float64             `json:"total_score_change" description:"Championship points gained or lost."`
+}
+
+// parseWhatIfRequest decodes and sanity checks the body before anything is fetched.
+func parseWhatIfRequest(request events.APIGatewayProxyRequest) (WhatIfRequestV1, error) {
+	body := []byte(request.Body)
+	if request.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(request.Body)
+		if err != nil {
+			return WhatIfRequestV1{}, fmt.Errorf("%w: body is not valid base64", ErrInvalidWhatIf)
+		}
+		body = decoded
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	var whatIf WhatIfRequestV1
+	if err := decoder.Decode(&whatIf); err != nil {
+		return WhatIfRequestV1{}, fmt.Errorf("%w: %w", ErrInvalidWhatIf, err)
+	}
+
+	if len(whatIf.Games) == 0 {
+		return WhatIfRequestV1{}, fmt.Errorf("%w: no games given", ErrInvalidWhatIf)
+	}
+	if len(whatIf.Games) > maxHypotheticalGames {
+		return WhatIfRequestV1{}, fmt.Errorf("%w: at most %d games", ErrInvalidWhatIf, maxHypotheticalGames)
+	}
+
+	for i, game := range whatIf.Games {
+		switch {
+		case game.Winner == "" || game.Loser == "":
+			return WhatIfRequestV1{}, fmt.Errorf("%w: game %d needs a winner and a loser", ErrInvalidWhatIf, i+1)
+		case game.Winner == game.Loser:
+			return WhatIfRequestV1{}, fmt.Errorf("%w: game %d has %s playing itself", ErrInvalidWhatIf, i+1,
+				game.Winner)
+		case (game.WinnerScore == nil) != (game.LoserScore == nil):
+			return WhatIfRequestV1{}, fmt.Errorf("%w: game %d needs both scores or neither", ErrInvalidWhatIf, i+1)
+		case game.WinnerScore != nil && *game.WinnerScore <= *game.LoserScore:
+			return WhatIfRequestV1{}, fmt.Errorf("%w: game %d winner_score must beat loser_score", ErrInvalidWhatIf,
+				i+1)
+		case game.WinnerScore != nil && *game.LoserScore < 0:
+			return WhatIfRequestV1{}, fmt.Errorf("%w: game %d scores can't be negative", ErrInvalidWhatIf, i+1)
+		case game.Margin != 0 && game.Margin < defaultWhatIfMargin:
+			return WhatIfRequestV1{}, fmt.Errorf("%w: game %d margin must be at least %.2f", ErrInvalidWhatIf, i+1,
+				defaultWhatIfMargin)
+		}
+	}
+
+	return whatIf, nil
+}
+
+// applyWhatIf plays the assumed results on a copy of the standings and rescores it. Each result must
+// be an unplayed game on the schedule, and each game can only be decided once. Median records only
+// move for weeks the request decides completely.
+func applyWhatIf(franchises Franchises, games []Game, scoring ScoringConfig,
+	whatIf WhatIfRequestV1) (Franchises, []AppliedGameV1, error) {
+	hypothetical := Franchises{Franchise: append([]Franchise{}, franchises.Franchise...)}
+	index := map[string]int{}
+	for i, franchise := range hypothetical.Franchise {
+		index[franchise.TeamID] = i
+	}
+
+	models := weeklyModels(games)
+	decided := map[Game]bool{}
+	applied := make([]AppliedGameV1, 0, len(whatIf.Games))
+
+	for i, result := range whatIf.Games {
+		game, ok := findScheduledGame(games, result)
+		switch {
+		case !ok:
+			return Franchises{}, nil, fmt.Errorf("%w: game %d, %s vs %s isn't on the week %d schedule",
+				ErrInvalidWhatIf, i+1, result.Winner, result.Loser, result.Week)
+		case game.Played:
+			return Franchises{}, nil, fmt.Errorf("%w: game %d, %s vs %s in week %d has already been played",
+				ErrInvalidWhatIf, i+1, result.Winner, result.Loser, result.Week)
+		case decided[game]:
+			return Franchises{}, nil, fmt.Errorf("%w: game %d, %s vs %s in week %d is given more than once",
+				ErrInvalidWhatIf, i+1, result.Winner, result.Loser, result.Week)
+		}
+		decided[game] = true
+
+		winner, winnerOK := index[result.Winner]
+		loser, loserOK := index[result.Loser]
+		if !winnerOK || !loserOK {
+			return Franchises{}, nil, fmt.Errorf("%w: game %d, %s vs %s isn't between franchises in the standings",
+				ErrInvalidWhatIf, i+1, result.Winner, result.Loser)
+		}
+
+		appliedGame := AppliedGameV1{Week: result.Week, Winner: result.Winner, Loser: result.Loser}
+		if result.WinnerScore != nil {
+			appliedGame.WinnerScore, appliedGame.LoserScore = *result.WinnerScore, *result.LoserScore
+		} else {
+			margin := result.Margin
+			if margin == 0 {
+				margin = defaultWhatIfMargin
+			}
+			appliedGame.LoserScore = roundFloat(modelFor(models, result.Loser).mean, 2)
+			appliedGame.WinnerScore = roundFloat(appliedGame.LoserScore+margin, 2)
+		}
+
+		playGame(&hypothetical.Franchise[winner], &hypothetical.Franchise[loser], appliedGame.WinnerScore,
+			appliedGame.LoserScore)
+		applied = append(applied, appliedGame)
+	}
+
+	if scoring.scoresMedian() {
+		playDecidedMedianWeeks(hypothetical, index, games, applied)
+	}
+
+	return rankFranchises(scoreChampionship(hypothetical, scoring), scoring), applied, nil
+}
+
+// playDecidedMedianWeeks scores the assumed results against their week's median. A week's median
+// depends on every score in it, so only weeks where every scheduled game was given are scored.
+func playDecidedMedianWeeks(franchises Franchises, index map[string]int, games []Game, applied []AppliedGameV1) {
+	scheduled := map[int]int{}
+	for _, game := range games {
+		scheduled[game.Week]++
+	}
+
+	decided := map[int][]Game{}
+	for _, game := range applied {
+		decided[game.Week] = append(decided[game.Week], Game{Week: game.Week, Home: game.Winner, Away: game.Loser,
+			HomeScore: game.WinnerScore, AwayScore: game.LoserScore, Played: true})
+	}
+
+	for week, weekGames := range decided {
+		if len(weekGames) == scheduled[week] {
+			playMedianWeek(franchises.Franchise, index, weekGames)
+		}
+	}
+	populateMedianRecords(franchises)
+}
+
+func findScheduledGame(games []Game, result HypotheticalGameV1) (Game, bool) {
+	for _, game := range games {
+		if game.Week != result.Week {
+			continue
+		}
+		if (game.Home == result.Winner && game.Away == result.Loser) ||
+			(game.Home == result.Loser && game.Away == result.Winner) {
+			return game, true
+		}
+	}
+
+	return Game{}, false
+}
+
+// diffStandings lines the hypothetical table up against the real one.
+func diffStandings(actual, hypothetical []FranchiseStandingV1) []WhatIfStandingV1 {
+	actualByID := map[string]FranchiseStandingV1{}
+	for _, standing := range actual {
+		actualByID[standing.FranchiseID] = standing
+	}
+
+	diff := make([]WhatIfStandingV1, 0, len(hypothetical))
+	for _, standing := range hypothetical {
+		current := actualByID[standing.FranchiseID]
+		diff = append(diff, WhatIfStandingV1{
+			Standing:         standing,
+			ActualRank:       current.Rank,
+			ActualTotalScore: current.TotalScore,
+			RankChange:       current.Rank - standing.Rank,
+			TotalScoreChange: roundFloat(standing.TotalScore-current.TotalScore, 2),
+		})
+	}
+
+	return diff
+}
+
+func printWhatIfTable(diff []WhatIfStandingV1) string {
+	t := table.NewWriter()
+	t.SetOutputMirror(&bytes.Buffer{})
+	t.AppendHeader(table.Row{"Rank", "Team", Record, FantasyPts, TotalPts, "Change", "Actual Rank"})
+	for _, d := range diff {
+		record := d.Standing.Record
+		t.AppendRow(table.Row{d.Standing.Rank, d.Standing.DisplayName,
+			formatRecord(record.Wins, record.Losses, record.Ties),
+			strconv.FormatFloat(d.Standing.PointsFor, 'f', 2, 64), formatScore(d.Standing.TotalScore),
+			formatChange(d.TotalScoreChange), d.ActualRank})
+	}
+
+	return t.Render()
+}
+
+func formatChange(change float64) string {
+	if change > 0 {
+		return "+" + formatScore(change)
+	}
+
+	return formatScore(change)
+}
+
+func serveWhatIf(ctx context.Context, request events.APIGatewayProxyRequest,
+	_ map[string]string) (events.APIGatewayProxyResponse, error) {
+	format, err := jsonOrTextFormat(request)
+	if err != nil {
+		return textResponse(http.StatusNotAcceptable, err.Error()), nil
+	}
+
+	whatIf, err := parseWhatIfRequest(request)
+	if err != nil {
+		return textResponse(http.StatusBadRequest, err.Error()), nil
+	}
+
+	standings, policy, err := standingsForRoute(ctx, "/what-if", request)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	games, err := fetchSchedule(ctx)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	hypothetical, applied, err := applyWhatIf(standings.Franchises, games, standings.Scoring, whatIf)
+	if errors.Is(err, ErrInvalidWhatIf) {
+		return textResponse(http.StatusUnprocessableEntity, err.Error()), nil
+	}
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+
+	now := time.Now()
+	actual := newStandingsResponseV1(standings, policy, now)
+	hypotheticalStandings := standings
+	hypotheticalStandings.Franchises = hypothetical
+	diff := diffStandings(actual.Franchises, newStandingsResponseV1(hypotheticalStandings, policy, now).Franchises)
+
+	if format == FormatJSON {
+		return jsonResponse(http.StatusOK, WhatIfResponseV1{
+			SchemaVersion: ResponseSchemaVersion,
+			Metadata:      actual.Metadata,
+			Games:         applied,
+			Franchises:    diff,
+		}, "application/json")
+	}
+
+	lines := make([]string, 0, len(applied))
+	for _, game := range applied {
+		lines = append(lines, fmt.Sprintf("Week %d: %s beats %s %s-%s", game.Week, game.Winner, game.Loser,
+			strconv.FormatFloat(game.WinnerScore, 'f', 2, 64), strconv.FormatFloat(game.LoserScore, 'f', 2, 64)))
+	}
+
+	return textResponse(http.StatusOK, strings.Join(lines, "\n")+"\n\n"+printWhatIfTable(diff)+
+		formatWarnings(standings.Warnings)), nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWhatIfRequest(t *testing.T) {
+	whatIf, err := parseWhatIfRequest(events.APIGatewayProxyRequest{
+		Body: `{"games":[{"week":14,"winner":"0003","loser":"0007","margin":10}]}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, WhatIfRequestV1{Games: []HypotheticalGameV1{
+		{Week: 14, Winner: "0003", Loser: "0007", Margin: 10},
+	}}, whatIf)
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(`{"games":[{"week":1,"winner":"0001","loser":"0002"}]}`))
+	_, err = parseWhatIfRequest(events.APIGatewayProxyRequest{Body: encoded, IsBase64Encoded: true})
+	assert.NoError(t, err)
+
+	for name, body := range map[string]string{
+		"not json":       `beats`,
+		"unknown field":  `{"games":[{"week":1,"winner":"0001","loser":"0002","tie":true}]}`,
+		"no games":       `{"games":[]}`,
+		"no loser":       `{"games":[{"week":1,"winner":"0001"}]}`,
+		"self":           `{"games":[{"week":1,"winner":"0001","loser":"0001"}]}`,
+		"one score":      `{"games":[{"week":1,"winner":"0001","loser":"0002","winner_score":100}]}`,
+		"winner loses":   `{"games":[{"week":1,"winner":"0001","loser":"0002","winner_score":90,"loser_score":100}]}`,
+		"negative score": `{"games":[{"week":1,"winner":"0001","loser":"0002","winner_score":1,"loser_score":-5}]}`,
+		"tiny margin":    `{"games":[{"week":1,"winner":"0001","loser":"0002","margin":0.001}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseWhatIfRequest(events.APIGatewayProxyRequest{Body: body})
+			assert.ErrorIs(t, err, ErrInvalidWhatIf)
+		})
+	}
+}
+
+func TestApplyWhatIf(t *testing.T) {
+	standings := scoredTiedStandings()
+	before := append([]Franchise{}, standings.Franchises.Franchise...)
+	// Going in: B 7.0, A 5.0, C 4.0 and D 4.0 with C ahead on AllPlay.
+	games := []Game{
+		{Week: 3, Home: "0001", Away: "0003", HomeScore: 100, AwayScore: 80, Played: true},
+		{Week: 4, Home: "0003", Away: "0004"},
+		{Week: 4, Home: "0002", Away: "0001"},
+	}
+	winnerScore, loserScore := 150.0, 20.0
+
+	whatIf := WhatIfRequestV1{Games: []HypotheticalGameV1{
+		{Week: 4, Winner: "0004", Loser: "0003", Margin: 10},
+		{Week: 4, Winner: "0001", Loser: "0002", WinnerScore: &winnerScore, LoserScore: &loserScore},
+	}}
+
+	hypothetical, applied, err := applyWhatIf(standings.Franchises, games, ScoringConfig{}, whatIf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []AppliedGameV1{
+		{Week: 4, Winner: "0004", Loser: "0003", WinnerScore: 90, LoserScore: 80},
+		{Week: 4, Winner: "0001", Loser: "0002", WinnerScore: 150, LoserScore: 20},
+	}, applied)
+	assert.Equal(t, before, standings.Franchises.Franchise, "the real standings are left alone")
+
+	// Fantasy points: A 250 (4.0), D 170 (3.0), C 160 (2.0), B 110 (1.0). Records: B and D share
+	// first and second at 3-1 (3.5 each), A and C share third and fourth at 2-2 (1.5 each).
+	ids := make([]string, 0, len(hypothetical.Franchise))
+	for _, franchise := range hypothetical.Franchise {
+		ids = append(ids, franchise.TeamID)
+	}
+	assert.Equal(t, []string{"0004", "0001", "0002", "0003"}, ids)
+	assert.Equal(t, 6.5, hypothetical.Franchise[0].TotalScore)
+}
+
+func TestApplyWhatIfRejectsGamesOffTheSchedule(t *testing.T) {
+	games := []Game{
+		{Week: 3, Home: "0001", Away: "0003", HomeScore: 100, AwayScore: 80, Played: true},
+		{Week: 4, Home: "0003", Away: "0004"},
+	}
+
+	for name, result := range map[string]HypotheticalGameV1{
+		"not scheduled":  {Week: 4, Winner: "0001", Loser: "0002"},
+		"wrong week":     {Week: 5, Winner: "0003", Loser: "0004"},
+		"already played": {Week: 3, Winner: "0003", Loser: "0001"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := applyWhatIf(scoredTiedStandings().Franchises, games, ScoringConfig{},
+				WhatIfRequestV1{Games: []HypotheticalGameV1{result}})
+			assert.ErrorIs(t, err, ErrInvalidWhatIf)
+		})
+	}
+
+	twice := WhatIfRequestV1{Games: []HypotheticalGameV1{
+		{Week: 4, Winner: "0003", Loser: "0004"}, {Week: 4, Winner: "0004", Loser: "0003"},
+	}}
+	_, _, err := applyWhatIf(scoredTiedStandings().Franchises, games, ScoringConfig{}, twice)
+	assert.ErrorContains(t, err, "more than once")
+}
+
+func TestDiffStandings(t *testing.T) {
+	now := time.Now()
+	policy := DisplayPolicy{Mode: DisplayFull}
+	actual := newStandingsResponseV1(testStandings(), policy, now).Franchises
+
+	swapped := testStandings()
+	swapped.Franchises.Franchise[0], swapped.Franchises.Franchise[1] =
+		swapped.Franchises.Franchise[1], swapped.Franchises.Franchise[0]
+	swapped.Franchises.Franchise[0].TotalScore = 5
+	hypothetical := newStandingsResponseV1(swapped, policy, now).Franchises
+
+	diff := diffStandings(actual, hypothetical)
+
+	assert.Equal(t, "0002", diff[0].Standing.FranchiseID)
+	assert.Equal(t, 1, diff[0].RankChange)
+	assert.Equal(t, 3.0, diff[0].TotalScoreChange)
+	assert.Equal(t, -1, diff[1].RankChange)
+	assert.Equal(t, 0.0, diff[1].TotalScoreChange)
+	assert.Contains(t, printWhatIfTable(diff), "+3.0")
+}
+
+func TestServeWhatIfRejectsBadBodyBeforeFetching(t *testing.T) {
+	response, err := serveWhatIf(context.Background(), events.APIGatewayProxyRequest{Body: `{}`}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, response.Body, "no games given")
+}
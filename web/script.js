@@ -20,23 +20,24 @@ async function logFetchScoring() {
 async function displayTeams() {
     const results = await fetchScoring();
     console.log(results);
-    const teams = results.franchise;
+    const teams = results.franchises;
     console.log(teams);
 
     teams.forEach((team) => {
+      const allPlay = team.all_play;
       const tr = document.createElement('tr');
       tr.classList.add('table-row');
       tr.innerHTML = `
       <tr>
-        <!-- <td scope="col" class="table-data">${team.name}</td> -->
-        <td scope="col" class="table-data">${team.owner_name.split(" ")[0]}</td>
-        <td scope="col" class="table-data">${team.Record}</td>
-        <td scope="col" class="table-data">${team.PointsFor}</td>
-        <td scope="col" class="table-data">${parseFloat(team.PointScore)}</td>
-        <td scope="col" class="table-data">${team.RecordScore}</td>
-        <td scope="col" class="table-data"><b>${team.TotalScore}</b></td>
-        <td scope="col" class="table-data">${team.AllPlayRecord}</td>
-        <td scope="col" class="table-data">${team.AllPlayPercentage}</td>
+        <!-- <td scope="col" class="table-data">${team.team_name}</td> -->
+        <td scope="col" class="table-data">${team.owner_alias || (team.owner_name || team.franchise_id).split(" ")[0]}</td>
+        <td scope="col" class="table-data">${team.record.wins}-${team.record.losses}-${team.record.ties}</td>
+        <td scope="col" class="table-data">${team.points_for}</td>
+        <td scope="col" class="table-data">${team.points_score}</td>
+        <td scope="col" class="table-data">${team.record_score}</td>
+        <td scope="col" class="table-data"><b>${team.total_score}</b></td>
+        <td scope="col" class="table-data">${allPlay ? `${allPlay.wins}-${allPlay.losses}-${allPlay.ties}` : ''}</td>
+        <td scope="col" class="table-data">${allPlay ? allPlay.percentage : ''}</td>
       </tr>
       `
 